@@ -69,3 +69,33 @@ func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (i
 	// This was a compound key and no interface matched it, let's return a map.
 	return keyMap, nil
 }
+
+func (*collectionAdapter) InsertBatch(col sqladapter.Collection, columns []string, rows [][]interface{}) ([]interface{}, error) {
+	pKey, err := col.PrimaryKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	q := col.SQL().InsertInto(col.Name()).Columns(columns...)
+	for i := range rows {
+		q = q.Values(rows[i]...)
+	}
+
+	// Rows are returned in the same order they were given in VALUES.
+	var keyMaps []db.Cond
+	if err := q.Returning(pKey...).Iterator().All(&keyMaps); err != nil {
+		return nil, err
+	}
+
+	ids := make([]interface{}, len(keyMaps))
+	for i := range keyMaps {
+		ids[i] = keyMaps[i][pKey[0]]
+	}
+	return ids, nil
+}
+
+// MaxPlaceholders returns the maximum number of parameters the wire protocol
+// allows in a single statement.
+func (*collectionAdapter) MaxPlaceholders() int {
+	return 65535
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cockroachdb
+
+import (
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// CopyFrom loads rows into the given table with COPY ... FROM STDIN, which is
+// much faster than INSERT when loading large amounts of data. Rows can be read
+// from a slice or a channel of maps, structs or []interface{} tuples, or from
+// a db.Iterator. Struct fields are mapped to columns with the session's field
+// mapping; if no columns are given they're taken from the first row, like
+// Insert does, and later rows can't have values for other columns. Rows are
+// loaded within the transaction when sess is one, with INSERT statements when
+// the driver is pgx.
+// CopyFrom returns the number of loaded rows.
+//
+// Example:
+//
+//	n, err := cockroachdb.CopyFrom(sess, "events", eventsChan)
+func CopyFrom(sess db.Session, table string, rows interface{}, columns ...string) (int64, error) {
+	sqlSess, ok := sess.(sqladapter.Session)
+	if !ok {
+		return 0, db.ErrNotSupportedByAdapter
+	}
+
+	ctx := sess.Context()
+	src, err := sqlbuilder.NewCopySource(ctx, sqlbuilder.MapperOf(sess), rows, columns)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	if len(src.Columns()) == 0 {
+		return 0, src.Err()
+	}
+
+	// The table name may be qualified with the name of its schema.
+	identifier := strings.SplitN(table, ".", 2)
+
+	n, err := copyFrom(ctx, sqlSess, identifier, src)
+	if err != nil {
+		return n, convertError(err)
+	}
+	return n, nil
+}
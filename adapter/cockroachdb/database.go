@@ -31,8 +31,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
-	pq "github.com/lib/pq"
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/sqladapter"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
@@ -140,16 +140,17 @@ func (*database) Err(err error) error {
 		if strings.Contains(s, `too many clients`) || strings.Contains(s, `remaining connection slots are reserved`) || strings.Contains(s, `too many open`) {
 			return db.ErrTooManyClients
 		}
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code {
-			case "25P02", "40001":
-				return db.ErrTransactionAborted
-			}
-		}
+		return convertError(err)
 	}
 	return err
 }
 
+// NormalizeTime converts automatic timestamps to UTC and truncates them to
+// the microsecond precision CockroachDB stores timestamps with.
+func (*database) NormalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
 func (*database) NewCollection() sqladapter.CollectionAdapter {
 	return &collectionAdapter{}
 }
@@ -27,9 +27,10 @@ package cockroachdb
 import (
 	"context"
 	"database/sql"
-
+	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/sqladapter"
@@ -51,3 +52,16 @@ func (*database) OpenDSN(sess sqladapter.Session, dsn string) (*sql.DB, error) {
 	}
 	return sql.Open("pgx", dsn)
 }
+
+func driverError(err error) (*pgError, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil, false
+	}
+	return &pgError{
+		code:       pgErr.Code,
+		constraint: pgErr.ConstraintName,
+		table:      pgErr.TableName,
+		column:     pgErr.ColumnName,
+	}, true
+}
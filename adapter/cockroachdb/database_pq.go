@@ -27,9 +27,10 @@ package cockroachdb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/sqladapter"
 )
@@ -50,3 +51,16 @@ func (*database) OpenDSN(sess sqladapter.Session, dsn string) (*sql.DB, error) {
 	}
 	return sql.Open("postgres", dsn)
 }
+
+func driverError(err error) (*pgError, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil, false
+	}
+	return &pgError{
+		code:       string(pqErr.Code),
+		constraint: pqErr.Constraint,
+		table:      pqErr.Table,
+		column:     pqErr.Column,
+	}, true
+}
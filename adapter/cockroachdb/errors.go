@@ -0,0 +1,71 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cockroachdb
+
+import (
+	db "github.com/upper/db/v4"
+)
+
+// pgError holds the fields of a driver error that describe what went wrong.
+type pgError struct {
+	code       string
+	constraint string
+	table      string
+	column     string
+}
+
+// pgErrorReasons maps CockroachDB error codes to portable errors, codes are
+// the same PostgreSQL uses.
+var pgErrorReasons = map[string]error{
+	"23505": db.ErrUniqueViolation,
+	"23503": db.ErrForeignKeyViolation,
+	"23502": db.ErrNotNullViolation,
+	"23514": db.ErrCheckViolation,
+	"40P01": db.ErrDeadlock,
+	"55P03": db.ErrLockTimeout,
+	"57014": db.ErrQueryCanceled,
+
+	// Transactions that must be retried.
+	"25P02": db.ErrTransactionAborted,
+	"40001": db.ErrTransactionAborted,
+}
+
+// convertError wraps driver errors that have a portable equivalent into a
+// *db.DatabaseError. Transactions that must be retried are reported as
+// db.ErrTransactionAborted.
+func convertError(err error) error {
+	pgErr, ok := driverError(err)
+	if !ok {
+		return err
+	}
+	reason, ok := pgErrorReasons[pgErr.code]
+	if !ok {
+		return err
+	}
+	return &db.DatabaseError{
+		Reason:     reason,
+		Constraint: pgErr.constraint,
+		Table:      pgErr.table,
+		Column:     pgErr.column,
+		Err:        err,
+	}
+}
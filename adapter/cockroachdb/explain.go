@@ -0,0 +1,132 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cockroachdb
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter"
+)
+
+// ExplainStatement runs EXPLAIN on the given query. CockroachDB does not
+// produce plans in a machine readable format, so the plan tree is built from
+// its text output.
+func (*database) ExplainStatement(sess sqladapter.Session, ctx context.Context, query string, args []interface{}, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	explain := "EXPLAIN "
+	if opts.Analyze {
+		explain = "EXPLAIN ANALYZE "
+	}
+
+	rows, err := sess.SQL().QueryContext(ctx, explain+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []string{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return newQueryPlan(lines), nil
+}
+
+// newQueryPlan parses the text output of EXPLAIN, which looks like:
+//
+//	distribution: local
+//
+//	• limit
+//	│ count: 1
+//	│
+//	└── • scan
+//	      table: artist@artist_pkey
+//
+// Every bullet starts a node, nodes are nested by the column the bullet is
+// at and "key: value" lines describe the node that precedes them.
+func newQueryPlan(lines []string) *db.QueryPlan {
+	plan := &db.QueryPlan{
+		Raw: strings.Join(lines, "\n"),
+	}
+
+	type level struct {
+		column int
+		node   *db.QueryPlanNode
+	}
+	stack := []level{}
+
+	for _, line := range lines {
+		if i := strings.Index(line, "•"); i >= 0 {
+			column := utf8.RuneCountInString(line[:i])
+			node := &db.QueryPlanNode{
+				Operation:  strings.TrimSpace(line[i+len("•"):]),
+				Properties: map[string]interface{}{},
+			}
+
+			for len(stack) > 0 && stack[len(stack)-1].column >= column {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1].node
+				parent.Children = append(parent.Children, node)
+			} else {
+				plan.Nodes = append(plan.Nodes, node)
+			}
+			stack = append(stack, level{column: column, node: node})
+			continue
+		}
+
+		if len(stack) == 0 {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.Trim(line, " │├└─"))
+		key, value, ok := strings.Cut(text, ": ")
+		if !ok {
+			continue
+		}
+
+		node := stack[len(stack)-1].node
+		if key == "table" {
+			// Tables are followed by the index being read, as in
+			// "artist@artist_pkey".
+			table, index, _ := strings.Cut(value, "@")
+			node.Table = table
+			if index != "" {
+				node.Properties["index"] = index
+			}
+			continue
+		}
+		node.Properties[key] = value
+	}
+
+	return plan
+}
@@ -0,0 +1,49 @@
+package cockroachdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueryPlan(t *testing.T) {
+	lines := []string{
+		"distribution: local",
+		"vectorized: true",
+		"",
+		"• limit",
+		"│ count: 1",
+		"│",
+		"└── • filter",
+		"    │ estimated row count: 1",
+		"    │ filter: name = 'Ozzie'",
+		"    │",
+		"    └── • scan",
+		"          missing stats",
+		"          table: artist@artist_pkey",
+		"          spans: FULL SCAN",
+	}
+
+	plan := newQueryPlan(lines)
+	assert.Equal(t, strings.Join(lines, "\n"), plan.Raw)
+
+	assert.Equal(t, 1, len(plan.Nodes))
+
+	limit := plan.Nodes[0]
+	assert.Equal(t, "limit", limit.Operation)
+	assert.Equal(t, "1", limit.Properties["count"])
+
+	assert.Equal(t, 1, len(limit.Children))
+	filter := limit.Children[0]
+	assert.Equal(t, "filter", filter.Operation)
+	assert.Equal(t, "name = 'Ozzie'", filter.Properties["filter"])
+
+	assert.Equal(t, 1, len(filter.Children))
+	scan := filter.Children[0]
+	assert.Equal(t, "scan", scan.Operation)
+	assert.Equal(t, "artist", scan.Table)
+	assert.Equal(t, "artist_pkey", scan.Properties["index"])
+	assert.Equal(t, "FULL SCAN", scan.Properties["spans"])
+	assert.Empty(t, scan.Children)
+}
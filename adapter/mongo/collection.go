@@ -24,6 +24,7 @@ package mongo
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
 	db "github.com/upper/db/v4"
@@ -36,6 +37,9 @@ import (
 type Collection struct {
 	parent     *Source
 	collection *mongo.Collection
+
+	softDeleteColumn string
+	scope            db.Cond
 }
 
 // Find creates a result set with the given conditions.
@@ -49,6 +53,8 @@ func (col *Collection) Find(terms ...interface{}) db.Result {
 		r.c = col
 		r.conditions = conditions
 		r.fields = fields
+		r.softDeleteColumn = col.softDeleteColumn
+		r.scope = col.scope
 		return nil
 	})
 
@@ -226,6 +232,38 @@ func (col *Collection) compileQuery(terms ...interface{}) interface{} {
 	return mapped
 }
 
+// SoftDelete returns a copy of the collection that soft deletes documents by
+// setting the given field.
+func (col *Collection) SoftDelete(column string) db.Collection {
+	copied := *col
+	copied.softDeleteColumn = column
+	return &copied
+}
+
+// SoftDeleteColumn returns the name of the soft delete field.
+func (col *Collection) SoftDeleteColumn() string {
+	return col.softDeleteColumn
+}
+
+// Scope returns a copy of the collection whose result sets only include the
+// documents that match the given conditions.
+func (col *Collection) Scope(cond db.Cond) db.Collection {
+	copied := *col
+	copied.scope = db.Cond{}
+	for k, v := range col.scope {
+		copied.scope[k] = v
+	}
+	for k, v := range cond {
+		copied.scope[k] = v
+	}
+	return &copied
+}
+
+// ScopeCond returns the conditions given to Scope.
+func (col *Collection) ScopeCond() db.Cond {
+	return col.scope
+}
+
 // Name returns the name of the table or tables that form the collection.
 func (col *Collection) Name() string {
 	return col.collection.Name()
@@ -261,6 +299,11 @@ func (col *Collection) UpdateReturning(item interface{}) error {
 func (col *Collection) Insert(item interface{}) (db.InsertResult, error) {
 	ctx := context.Background()
 
+	item, err := col.withScopeValues(item)
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := col.collection.InsertOne(ctx, item)
 	if err != nil {
 		return nil, err
@@ -269,6 +312,42 @@ func (col *Collection) Insert(item interface{}) (db.InsertResult, error) {
 	return db.NewInsertResult(res.InsertedID), nil
 }
 
+// InsertMany inserts the items of the given slice in order and returns their
+// IDs.
+func (col *Collection) InsertMany(items interface{}) ([]db.ID, error) {
+	ctx := context.Background()
+
+	v := reflect.ValueOf(items)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("upper: expecting a slice of items but got %T", items)
+	}
+	if v.Len() == 0 {
+		return nil, nil
+	}
+
+	docs := make([]interface{}, v.Len())
+	for i := range docs {
+		var err error
+		if docs[i], err = col.withScopeValues(v.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := col.collection.InsertMany(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]db.ID, len(res.InsertedIDs))
+	for i := range res.InsertedIDs {
+		ids[i] = res.InsertedIDs[i]
+	}
+	return ids, nil
+}
+
 // Exists returns true if the collection exists.
 func (col *Collection) Exists() (bool, error) {
 	ctx := context.Background()
@@ -289,3 +368,36 @@ func (col *Collection) Exists() (bool, error) {
 
 	return hasNext, nil
 }
+
+// withScopeValues returns the item as a document with the fields of the scope
+// that are compared to plain values set.
+func (col *Collection) withScopeValues(item interface{}) (interface{}, error) {
+	values := bson.M{}
+	for key, value := range col.scope {
+		field, ok := key.(string)
+		if !ok || strings.ContainsAny(field, " =<>!$") {
+			continue
+		}
+		switch value.(type) {
+		case *db.Comparison, db.LogicalExpr, *db.RawExpr, bson.M, nil:
+			continue
+		}
+		values[field] = value
+	}
+	if len(values) == 0 {
+		return item, nil
+	}
+
+	data, err := bson.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for field, value := range values {
+		doc[field] = value
+	}
+	return doc, nil
+}
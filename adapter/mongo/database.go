@@ -43,6 +43,10 @@ const Adapter = `mongo`
 
 var connTimeout = time.Second * 5
 
+// slowQueryThreshold is the slow query threshold of MongoDB sessions, unless a
+// different one is set in db.DefaultSettings.
+var slowQueryThreshold = time.Millisecond * 100
+
 // Source represents a MongoDB database.
 type Source struct {
 	db.Settings
@@ -73,6 +77,9 @@ func init() {
 func Open(connURL db.ConnectionURL) (db.Session, error) {
 	ctx := context.Background()
 	settings := db.NewSettings()
+	if settings.SlowQueryThreshold() == db.DefaultSlowQueryThreshold {
+		settings.SetSlowQueryThreshold(slowQueryThreshold)
+	}
 
 	d := &Source{
 		Settings: settings,
@@ -138,6 +145,7 @@ func (s *Source) Clone() (db.Session, error) {
 		version:     s.version,
 		collections: map[string]*Collection{},
 	}
+	copySettings(s, clone)
 
 	if err := clone.open(); err != nil {
 		return nil, err
@@ -146,6 +154,18 @@ func (s *Source) Clone() (db.Session, error) {
 	return clone, nil
 }
 
+// copySettings copies the settings of a session that apply to MongoDB.
+func copySettings(from db.Settings, into db.Settings) {
+	into.SetSlowQueryThreshold(from.SlowQueryThreshold())
+	into.SetQueryArgsLogging(from.QueryArgsLoggingEnabled())
+	into.SetQueryStackCapture(from.QueryStackCaptureEnabled())
+	into.SetQueryLogLevel(from.QueryLogLevel())
+	into.SetClock(from.Clock())
+	into.SetStrictMapping(from.StrictMappingEnabled())
+	into.SetRecordTracking(from.RecordTrackingEnabled())
+	into.SetFieldMapping(from.FieldMapping())
+}
+
 // Ping checks whether a connection to the database is still alive by pinging
 // it, establishing a connection if necessary.
 func (s *Source) Ping() error {
@@ -231,6 +251,22 @@ func (s *Source) Save(db.Record) error {
 	return db.ErrNotImplemented
 }
 
+func (s *Source) Changed(db.Record) []string {
+	return nil
+}
+
+func (s *Source) CreateAll(interface{}) error {
+	return db.ErrNotImplemented
+}
+
+func (s *Source) SaveAll(interface{}) error {
+	return db.ErrNotImplemented
+}
+
+func (s *Source) DeleteAll(interface{}) error {
+	return db.ErrNotImplemented
+}
+
 func (s *Source) Context() context.Context {
 	return s.ctx
 }
@@ -0,0 +1,180 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Explain describes the plan MongoDB would use to find the documents of the
+// result set.
+func (res *result) Explain(ctx context.Context, opts *db.ExplainOptions) (plan *db.QueryPlan, err error) {
+	rq, err := res.build()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rq.groupBy) > 0 {
+		return nil, db.ErrUnsupported
+	}
+
+	if ctx == nil {
+		ctx = rq.c.parent.Context()
+	}
+	if opts == nil {
+		opts = &db.ExplainOptions{}
+	}
+
+	defer func(start time.Time) {
+		queryLog(rq.c.parent, &db.QueryStatus{
+			RawQuery: rq.debugQuery("Explain"),
+			Err:      err,
+			Start:    start,
+			End:      time.Now(),
+			Context:  ctx,
+		})
+	}(time.Now())
+
+	verbosity := "queryPlanner"
+	if opts.Analyze {
+		verbosity = "executionStats"
+	}
+
+	cmd := bson.D{
+		{Key: "explain", Value: rq.findCommand()},
+		{Key: "verbosity", Value: verbosity},
+	}
+
+	var out bson.M
+	if err = rq.c.parent.database.RunCommand(ctx, cmd).Decode(&out); err != nil {
+		return nil, err
+	}
+
+	return newQueryPlan(rq.c.collection.Name(), out)
+}
+
+// findCommand returns the find command that matches the query.
+func (r *resultQuery) findCommand() bson.D {
+	limit, offset := r.limit, r.offset
+	if r.pageSize > 0 {
+		offset = int(r.pageSize * r.pageNumber)
+		limit = int(r.pageSize)
+	}
+
+	cmd := bson.D{
+		{Key: "find", Value: r.c.collection.Name()},
+		{Key: "filter", Value: r.conditions},
+	}
+
+	sort := bson.D{}
+	for _, field := range r.sort {
+		key, value := field, 1
+		if key[0] == '-' {
+			key, value = key[1:], -1
+		}
+		sort = append(sort, bson.E{Key: key, Value: value})
+	}
+	if r.cursorReverseOrder {
+		sort = bson.D{{Key: "_id", Value: -1}}
+	}
+	if len(sort) > 0 {
+		cmd = append(cmd, bson.E{Key: "sort", Value: sort})
+	}
+
+	if offset > 0 {
+		cmd = append(cmd, bson.E{Key: "skip", Value: int64(offset)})
+	}
+	if limit > 0 {
+		cmd = append(cmd, bson.E{Key: "limit", Value: int64(limit)})
+	}
+
+	return cmd
+}
+
+// newQueryPlan converts the output of the explain command on the given
+// collection into a query plan.
+// When execution statistics are present they are used instead of the winning
+// plan, as they describe the same stages plus run-time figures.
+func newQueryPlan(collection string, out bson.M) (*db.QueryPlan, error) {
+	raw, err := bson.MarshalExtJSON(out, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &db.QueryPlan{Raw: string(raw)}
+
+	var root interface{}
+	if stats, ok := out["executionStats"].(bson.M); ok {
+		root = stats["executionStages"]
+	}
+	if root == nil {
+		if planner, ok := out["queryPlanner"].(bson.M); ok {
+			root = planner["winningPlan"]
+		}
+	}
+
+	if stage, ok := root.(bson.M); ok {
+		plan.Nodes = []*db.QueryPlanNode{newQueryPlanNode(collection, stage)}
+	}
+
+	return plan, nil
+}
+
+func newQueryPlanNode(collection string, stage bson.M) *db.QueryPlanNode {
+	node := &db.QueryPlanNode{
+		Properties: map[string]interface{}{},
+	}
+
+	for key, value := range stage {
+		switch key {
+		case "stage":
+			node.Operation, _ = value.(string)
+		case "inputStage":
+			if child, ok := value.(bson.M); ok {
+				node.Children = append(node.Children, newQueryPlanNode(collection, child))
+			}
+		case "inputStages":
+			if children, ok := value.(bson.A); ok {
+				for i := range children {
+					if child, ok := children[i].(bson.M); ok {
+						node.Children = append(node.Children, newQueryPlanNode(collection, child))
+					}
+				}
+			}
+		default:
+			node.Properties[key] = value
+		}
+	}
+
+	// Only scans read from the collection, other stages take their input from
+	// child stages.
+	if strings.HasSuffix(node.Operation, "SCAN") {
+		node.Table = collection
+	}
+
+	return node
+}
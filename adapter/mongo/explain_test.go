@@ -0,0 +1,66 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNewQueryPlan(t *testing.T) {
+	out := bson.M{
+		"queryPlanner": bson.M{
+			"namespace": "test.artist",
+			"winningPlan": bson.M{
+				"stage":       "LIMIT",
+				"limitAmount": int32(1),
+				"inputStage": bson.M{
+					"stage": "FETCH",
+					"inputStage": bson.M{
+						"stage":     "IXSCAN",
+						"indexName": "name_1",
+					},
+				},
+			},
+		},
+	}
+
+	plan, err := newQueryPlan("artist", out)
+	assert.NoError(t, err)
+	assert.Contains(t, plan.Raw, `"winningPlan"`)
+
+	assert.Equal(t, 1, len(plan.Nodes))
+	assert.Equal(t, "LIMIT", plan.Nodes[0].Operation)
+	assert.Equal(t, "", plan.Nodes[0].Table)
+	assert.Equal(t, int32(1), plan.Nodes[0].Properties["limitAmount"])
+
+	fetch := plan.Nodes[0].Children[0]
+	assert.Equal(t, "FETCH", fetch.Operation)
+
+	scan := fetch.Children[0]
+	assert.Equal(t, "IXSCAN", scan.Operation)
+	assert.Equal(t, "artist", scan.Table)
+	assert.Equal(t, "name_1", scan.Properties["indexName"])
+	assert.Empty(t, scan.Children)
+}
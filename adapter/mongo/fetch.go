@@ -0,0 +1,217 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var errExpectingSingleField = errors.New(`argument can only hold the value of a single field`)
+
+var (
+	tupleType = reflect.TypeOf([]interface{}{})
+	timeType  = reflect.TypeOf(time.Time{})
+)
+
+// isScalar reports whether values of type t hold a single field instead of
+// being decoded from the whole document like maps and structs are.
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Ptr, reflect.Interface:
+		return false
+	case reflect.Struct:
+		return t == timeType
+	}
+	return t != tupleType
+}
+
+// decode decodes the current document of the cursor into dst, which can also
+// point to a scalar or to a tuple. If strict is true the fields of the
+// document must match the fields of structs, see checkMapping.
+func decode(cur *mongo.Cursor, dst interface{}, strict bool) error {
+	dstv := reflect.ValueOf(dst)
+	if dstv.Kind() != reflect.Ptr || dstv.IsNil() {
+		return cur.Decode(dst)
+	}
+
+	itemT := dstv.Elem().Type()
+	if itemT != tupleType && !isScalar(itemT) {
+		if strict {
+			if err := checkMapping(cur.Current, itemT); err != nil {
+				return err
+			}
+		}
+		return cur.Decode(dst)
+	}
+	return decodeValue(cur.Current, dstv.Elem())
+}
+
+// decodeAll decodes all the documents of the cursor into the slice dst points
+// to, its elements can also be scalars or tuples.
+func decodeAll(ctx context.Context, cur *mongo.Cursor, dst interface{}, strict bool) error {
+	dstv := reflect.ValueOf(dst)
+	if dstv.Kind() != reflect.Ptr || dstv.IsNil() || dstv.Elem().Kind() != reflect.Slice {
+		return cur.All(ctx, dst)
+	}
+
+	slicev := dstv.Elem()
+	itemT := slicev.Type().Elem()
+	if itemT != tupleType && !isScalar(itemT) && !strict {
+		return cur.All(ctx, dst)
+	}
+
+	defer cur.Close(ctx)
+
+	slicev = reflect.MakeSlice(slicev.Type(), 0, slicev.Cap())
+	for cur.Next(ctx) {
+		item := reflect.New(itemT)
+		if err := decode(cur, item.Interface(), strict); err != nil {
+			return err
+		}
+		slicev = reflect.Append(slicev, item.Elem())
+	}
+	dstv.Elem().Set(slicev)
+
+	return cur.Err()
+}
+
+// decodeValue decodes the only field of the given document into the
+// addressable value v, or all of its fields by position if v is a tuple.
+func decodeValue(doc bson.Raw, v reflect.Value) error {
+	elems, err := doc.Elements()
+	if err != nil {
+		return err
+	}
+
+	if v.Type() == tupleType {
+		tuple := make([]interface{}, len(elems))
+		for i := range elems {
+			if err := elems[i].Value().Unmarshal(&tuple[i]); err != nil {
+				return err
+			}
+		}
+		v.Set(reflect.ValueOf(tuple))
+		return nil
+	}
+
+	if len(elems) != 1 {
+		return errExpectingSingleField
+	}
+	if elems[0].Value().Type == bson.TypeNull {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	return elems[0].Value().Unmarshal(v.Addr().Interface())
+}
+
+// checkMapping returns a *db.MappingError if the fields of doc don't match the
+// fields of itemT, when it's a struct. The _id field is ignored unless itemT
+// maps it, as it's part of documents unless explicitly excluded.
+func checkMapping(doc bson.Raw, itemT reflect.Type) error {
+	structT := reflectx.Deref(itemT)
+	if structT.Kind() != reflect.Struct || structT == timeType {
+		return nil
+	}
+
+	elems, err := doc.Elements()
+	if err != nil {
+		return err
+	}
+
+	typeMap := mapper.TypeMap(structT)
+	mappingErr := &db.MappingError{Type: structT.String()}
+
+	present := make(map[string]bool, len(elems))
+	for _, elem := range elems {
+		key := elem.Key()
+		present[key] = true
+		if _, ok := typeMap.Names[key]; !ok && key != "_id" {
+			mappingErr.UnmappedColumns = append(mappingErr.UnmappedColumns, key)
+		}
+	}
+
+	for _, fi := range typeMap.Index {
+		if fi.Name == "" || fi.Embedded || strings.Contains(fi.Path, ".") || present[fi.Path] {
+			continue
+		}
+		if isOptionalField(fi) {
+			continue
+		}
+		mappingErr.MissingFields = append(mappingErr.MissingFields, fi.Path)
+	}
+
+	if len(mappingErr.UnmappedColumns) > 0 || len(mappingErr.MissingFields) > 0 {
+		return mappingErr
+	}
+	return nil
+}
+
+// isOptionalField reports whether fi is tagged with the optional option,
+// either in its bson or in its db tag.
+func isOptionalField(fi *reflectx.FieldInfo) bool {
+	if _, ok := fi.Options["optional"]; ok {
+		return true
+	}
+	opts := strings.Split(fi.Field.Tag.Get("db"), ",")
+	return slices.Contains(opts[1:], "optional")
+}
+
+// chunkKey returns the value the key field has on the last document of the
+// given chunk.
+func chunkKey(batch interface{}, key string) (interface{}, error) {
+	items := reflect.ValueOf(batch).Elem()
+	item := reflect.Indirect(items.Index(items.Len() - 1))
+
+	switch item.Kind() {
+	case reflect.Map:
+		if item.Type().Key().Kind() == reflect.String {
+			value := item.MapIndex(reflect.ValueOf(key).Convert(item.Type().Key()))
+			if value.IsValid() {
+				return value.Interface(), nil
+			}
+		}
+	case reflect.Struct:
+		if fi, ok := mapper.TypeMap(item.Type()).Names[key]; ok {
+			return reflectx.FieldByIndexesReadOnly(item, fi.Index).Interface(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("upper: chunk documents have no value for key %q", key)
+}
@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -38,12 +39,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/upper/db/v4/internal/immutable"
+	"github.com/upper/db/v4/internal/preload"
+	"github.com/upper/db/v4/internal/reflectx"
 )
 
 type resultQuery struct {
 	c *Collection
 
 	fields     []string
+	distinct   bool
 	limit      int
 	offset     int
 	sort       []string
@@ -56,10 +60,31 @@ type resultQuery struct {
 	cursorValue        interface{}
 	cursorCond         db.Cond
 	cursorReverseOrder bool
+
+	softDeleteColumn string
+	softDeleteScope  softDeleteScope
+
+	scope    db.Cond
+	unscoped bool
+
+	preload []string
+
+	strictMapping *bool
 }
 
+// softDeleteScope determines which documents of a soft delete collection are
+// part of a result set.
+type softDeleteScope uint8
+
+const (
+	softDeleteScopeExcludeDeleted softDeleteScope = iota
+	softDeleteScopeWithDeleted
+	softDeleteScopeOnlyDeleted
+)
+
 type result struct {
-	cur *mongo.Cursor
+	cur       *mongo.Cursor
+	curStrict bool
 
 	err   error
 	errMu sync.Mutex
@@ -70,6 +95,10 @@ type result struct {
 
 var _ = immutable.Immutable(&result{})
 
+// mapper maps document fields to struct fields the same way the bson codec
+// does.
+var mapper = reflectx.NewMapperFunc("bson", strings.ToLower)
+
 func (res *result) frame(fn func(*resultQuery) error) *result {
 	return &result{prev: res, fn: fn}
 }
@@ -105,6 +134,50 @@ func (res *result) Where(terms ...interface{}) db.Result {
 	})
 }
 
+// WithDeleted includes soft deleted documents in the result set.
+func (res *result) WithDeleted() db.Result {
+	return res.frame(func(r *resultQuery) error {
+		r.softDeleteScope = softDeleteScopeWithDeleted
+		return nil
+	})
+}
+
+// Unscoped lifts the conditions the collection was scoped with.
+func (res *result) Unscoped() db.Result {
+	return res.frame(func(r *resultQuery) error {
+		r.unscoped = true
+		return nil
+	})
+}
+
+// StrictMapping overrides the strict mapping setting of the session for this
+// result set.
+func (res *result) StrictMapping(value bool) db.Result {
+	return res.frame(func(r *resultQuery) error {
+		r.strictMapping = &value
+		return nil
+	})
+}
+
+// strict reports whether documents must map exactly into structs.
+func (r *resultQuery) strict() bool {
+	if r.strictMapping != nil {
+		return *r.strictMapping
+	}
+	return r.c.parent.StrictMappingEnabled()
+}
+
+// OnlyDeleted limits the result set to soft deleted documents.
+func (res *result) OnlyDeleted() db.Result {
+	return res.frame(func(r *resultQuery) error {
+		if r.softDeleteColumn == "" {
+			return db.ErrMissingSoftDeleteColumn
+		}
+		r.softDeleteScope = softDeleteScopeOnlyDeleted
+		return nil
+	})
+}
+
 func (res *result) Paginate(pageSize uint) db.Result {
 	return res.frame(func(r *resultQuery) error {
 		r.pageSize = pageSize
@@ -219,6 +292,22 @@ func (res *result) Select(fields ...interface{}) db.Result {
 	})
 }
 
+// Distinct makes the result set return documents with unique values on the
+// given fields only. Documents are always unique when no fields are given, as
+// they're identified by _id.
+func (res *result) Distinct(fields ...interface{}) db.Result {
+	return res.frame(func(r *resultQuery) error {
+		r.distinct = true
+		if len(fields) > 0 {
+			r.fields = make([]string, 0, len(fields))
+			for i := range fields {
+				r.fields = append(r.fields, fmt.Sprintf(`%v`, fields[i]))
+			}
+		}
+		return nil
+	})
+}
+
 // All dumps all results into a pointer to an slice of structs or maps.
 func (res *result) All(dst interface{}) error {
 	rq, err := res.build()
@@ -232,7 +321,7 @@ func (res *result) All(dst interface{}) error {
 	}
 
 	defer func(start time.Time) {
-		queryLog(&db.QueryStatus{
+		queryLog(rq.c.parent, &db.QueryStatus{
 			RawQuery: rq.debugQuery("Find.All"),
 			Err:      err,
 			Start:    start,
@@ -240,12 +329,15 @@ func (res *result) All(dst interface{}) error {
 		})
 	}(time.Now())
 
-	err = q.All(context.Background(), dst)
+	err = decodeAll(context.Background(), q, dst, rq.strict())
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return db.ErrNoMoreRows
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	return rq.preloadRelations(dst)
 }
 
 // GroupBy is used to group results that have the same value in the same column
@@ -272,7 +364,7 @@ func (res *result) One(dst interface{}) error {
 	}
 
 	defer func(start time.Time) {
-		queryLog(&db.QueryStatus{
+		queryLog(rq.c.parent, &db.QueryStatus{
 			RawQuery: rq.debugQuery("Find.One"),
 			Err:      err,
 			Start:    start,
@@ -289,12 +381,31 @@ func (res *result) One(dst interface{}) error {
 
 	defer q.Close(ctx)
 
-	err = q.Decode(dst)
+	err = decode(q, dst, rq.strict())
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return db.ErrNoMoreRows
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	return rq.preloadRelations(dst)
+}
+
+// Preload loads the given relations into the documents fetched by All and
+// One.
+func (res *result) Preload(relations ...string) db.Result {
+	return res.frame(func(r *resultQuery) error {
+		r.preload = append(r.preload, relations...)
+		return nil
+	})
+}
+
+func (r *resultQuery) preloadRelations(dst interface{}) error {
+	if len(r.preload) == 0 {
+		return nil
+	}
+	return preload.Load(r.c.parent, mapper, dst, r.preload)
 }
 
 func (res *result) Err() error {
@@ -325,7 +436,7 @@ func (res *result) Next(dst interface{}) bool {
 		}
 
 		defer func(start time.Time) {
-			queryLog(&db.QueryStatus{
+			queryLog(rq.c.parent, &db.QueryStatus{
 				RawQuery: rq.debugQuery("Find.Next"),
 				Err:      err,
 				Start:    start,
@@ -334,6 +445,7 @@ func (res *result) Next(dst interface{}) bool {
 		}(time.Now())
 
 		res.cur = q
+		res.curStrict = rq.strict()
 	}
 
 	if !res.cur.Next(ctx) {
@@ -341,7 +453,7 @@ func (res *result) Next(dst interface{}) bool {
 		return false
 	}
 
-	err := res.cur.Decode(dst)
+	err := decode(res.cur, dst, res.curStrict)
 	if err != nil {
 		res.setErr(err)
 		return false
@@ -350,30 +462,64 @@ func (res *result) Next(dst interface{}) bool {
 	return true
 }
 
-// Delete remove the matching items from the collection.
+// Delete remove the matching items from the collection, or sets their soft
+// delete field if the collection soft deletes documents.
 func (res *result) Delete() error {
+	_, err := res.DeleteCount()
+	return err
+}
+
+// DeleteCount removes or soft deletes the matching items from the collection
+// and returns how many were deleted.
+func (res *result) DeleteCount() (uint64, error) {
 	ctx := context.Background()
 
 	rq, err := res.build()
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if rq.softDeleteColumn != "" {
+		return rq.softDelete(ctx)
+	}
+
+	return rq.hardDelete(ctx)
+}
+
+// HardDelete removes the matching items from the collection, even if the
+// collection soft deletes documents.
+func (res *result) HardDelete() error {
+	_, err := res.HardDeleteCount()
+	return err
+}
+
+// HardDeleteCount removes the matching items from the collection and returns
+// how many were removed.
+func (res *result) HardDeleteCount() (uint64, error) {
+	rq, err := res.build()
+	if err != nil {
+		return 0, err
 	}
 
+	return rq.hardDelete(context.Background())
+}
+
+func (r *resultQuery) hardDelete(ctx context.Context) (deleted uint64, err error) {
 	defer func(start time.Time) {
-		queryLog(&db.QueryStatus{
-			RawQuery: rq.debugQuery("Remove"),
+		queryLog(r.c.parent, &db.QueryStatus{
+			RawQuery: r.debugQuery("Remove"),
 			Err:      err,
 			Start:    start,
 			End:      time.Now(),
 		})
 	}(time.Now())
 
-	_, err = rq.c.collection.DeleteMany(ctx, rq.conditions)
+	res, err := r.c.collection.DeleteMany(ctx, r.conditions)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return uint64(res.DeletedCount), nil
 }
 
 // Close closes the result set.
@@ -391,18 +537,28 @@ func (r *result) Close() error {
 
 // Update modified matching items from the collection with values of the given
 // map or struct.
-func (res *result) Update(src interface{}) (err error) {
+func (res *result) Update(src interface{}) error {
+	_, err := res.UpdateCount(src)
+	return err
+}
+
+// UpdateCount modifies the matching items from the collection and returns how
+// many were modified.
+func (res *result) UpdateCount(src interface{}) (modified uint64, err error) {
 	ctx := context.Background()
 
-	updateSet := map[string]interface{}{"$set": src}
+	update, err := updateDocument(src)
+	if err != nil {
+		return 0, err
+	}
 
 	rq, err := res.build()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	defer func(start time.Time) {
-		queryLog(&db.QueryStatus{
+		queryLog(rq.c.parent, &db.QueryStatus{
 			RawQuery: rq.debugQuery("Update"),
 			Err:      err,
 			Start:    start,
@@ -410,11 +566,114 @@ func (res *result) Update(src interface{}) (err error) {
 		})
 	}(time.Now())
 
-	_, err = rq.c.collection.UpdateMany(ctx, rq.conditions, updateSet)
+	updated, err := rq.c.collection.UpdateMany(ctx, rq.conditions, update)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(updated.ModifiedCount), nil
+}
+
+// updateDocument returns the update that sets the values of src. Maps that
+// reference fields with db.Column are turned into update pipelines.
+func updateDocument(src interface{}) (interface{}, error) {
+	v := reflect.ValueOf(src)
+	if v.Kind() != reflect.Map {
+		return bson.M{"$set": src}, nil
+	}
+
+	set := bson.M{}
+	isPipeline := false
+	for iter := v.MapRange(); iter.Next(); {
+		key := fmt.Sprintf("%v", iter.Key().Interface())
+		switch value := iter.Value().Interface().(type) {
+		case *db.ColumnExpr:
+			set[key] = "$" + value.Name()
+			isPipeline = true
+		case *db.RawExpr, *db.FuncExpr:
+			return nil, db.ErrNotSupportedByAdapter
+		default:
+			// Strings starting with $ would be taken as field paths otherwise.
+			set[key] = bson.M{"$literal": value}
+		}
+	}
+
+	if !isPipeline {
+		return bson.M{"$set": src}, nil
+	}
+	return mongo.Pipeline{{{Key: "$set", Value: set}}}, nil
+}
+
+// Increment adds n to the given field of the matching documents.
+func (res *result) Increment(field string, n interface{}) error {
+	return res.inc(field, n)
+}
+
+// Decrement subtracts n from the given field of the matching documents.
+func (res *result) Decrement(field string, n interface{}) error {
+	n, err := negate(n)
 	if err != nil {
 		return err
 	}
-	return nil
+	return res.inc(field, n)
+}
+
+func (res *result) inc(field string, n interface{}) (err error) {
+	ctx := context.Background()
+
+	rq, err := res.build()
+	if err != nil {
+		return err
+	}
+
+	defer func(start time.Time) {
+		queryLog(rq.c.parent, &db.QueryStatus{
+			RawQuery: rq.debugQuery("Update"),
+			Err:      err,
+			Start:    start,
+			End:      time.Now(),
+		})
+	}(time.Now())
+
+	_, err = rq.c.collection.UpdateMany(ctx, rq.conditions, bson.M{"$inc": bson.M{field: n}})
+	return err
+}
+
+// negate returns the additive inverse of the given number.
+func negate(n interface{}) (interface{}, error) {
+	v := reflect.ValueOf(n)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return -v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return -int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return -v.Float(), nil
+	}
+	return nil, db.ErrUnsupportedValue
+}
+
+func (r *resultQuery) softDelete(ctx context.Context) (deleted uint64, err error) {
+	// Documents that were already deleted keep their original deletion time.
+	if err := r.and(db.Cond{r.softDeleteColumn: nil}); err != nil {
+		return 0, err
+	}
+
+	updateSet := bson.M{"$set": bson.M{r.softDeleteColumn: r.c.parent.Clock()()}}
+
+	defer func(start time.Time) {
+		queryLog(r.c.parent, &db.QueryStatus{
+			RawQuery: r.debugQuery("Update"),
+			Err:      err,
+			Start:    start,
+			End:      time.Now(),
+		})
+	}(time.Now())
+
+	updated, err := r.c.collection.UpdateMany(ctx, r.conditions, updateSet)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(updated.ModifiedCount), nil
 }
 
 func (res *result) build() (*resultQuery, error) {
@@ -424,6 +683,25 @@ func (res *result) build() (*resultQuery, error) {
 	}
 
 	rq := rqi.(*resultQuery)
+	if len(rq.scope) > 0 && !rq.unscoped {
+		if err := rq.and(rq.scope); err != nil {
+			return nil, err
+		}
+	}
+
+	if rq.softDeleteColumn != "" {
+		switch rq.softDeleteScope {
+		case softDeleteScopeExcludeDeleted:
+			if err := rq.and(db.Cond{rq.softDeleteColumn: nil}); err != nil {
+				return nil, err
+			}
+		case softDeleteScopeOnlyDeleted:
+			if err := rq.and(db.Cond{rq.softDeleteColumn: bson.M{"$ne": nil}}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if !rq.cursorCond.Empty() {
 		if err := rq.and(rq.cursorCond); err != nil {
 			return nil, err
@@ -460,6 +738,10 @@ func (r *resultQuery) query() (*mongo.Cursor, error) {
 		r.limit = int(r.pageSize)
 	}
 
+	if r.distinct {
+		return r.queryDistinct(ctx)
+	}
+
 	if r.offset > 0 {
 		opts.SetSkip(int64(r.offset))
 	}
@@ -469,15 +751,7 @@ func (r *resultQuery) query() (*mongo.Cursor, error) {
 	}
 
 	if len(r.sort) > 0 {
-		sort := bson.D{}
-		for _, field := range r.sort {
-			key, value := field, 1
-			if key[0] == '-' {
-				key, value = key[1:], -1
-			}
-			sort = append(sort, bson.E{Key: key, Value: value})
-		}
-		opts.SetSort(sort)
+		opts.SetSort(r.sortFields())
 	}
 
 	selectedFields := bson.M{}
@@ -495,7 +769,11 @@ func (r *resultQuery) query() (*mongo.Cursor, error) {
 	}
 
 	if len(selectedFields) > 0 {
-		panic("not implemented")
+		// _id is returned unless it's explicitly excluded.
+		if _, ok := selectedFields["_id"]; !ok {
+			selectedFields["_id"] = false
+		}
+		opts.SetProjection(selectedFields)
 	}
 
 	q, err := r.c.collection.Find(ctx, r.conditions, opts)
@@ -506,6 +784,129 @@ func (r *resultQuery) query() (*mongo.Cursor, error) {
 	return q, nil
 }
 
+// sortFields returns the sort specification of the result set.
+func (r *resultQuery) sortFields() bson.D {
+	sort := bson.D{}
+	for _, field := range r.sort {
+		key, value := field, 1
+		if key[0] == '-' {
+			key, value = key[1:], -1
+		}
+		sort = append(sort, bson.E{Key: key, Value: value})
+	}
+	return sort
+}
+
+// pipeline returns the aggregation stages that produce the documents of the
+// result set, followed by the given stages.
+func (r *resultQuery) pipeline(stages ...bson.D) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: r.conditions}},
+	}
+
+	if r.distinct && len(r.fields) > 0 {
+		group := bson.D{}
+		for _, field := range r.fields {
+			if field == `*` {
+				group = nil
+				break
+			}
+			group = append(group, bson.E{Key: field, Value: "$" + field})
+		}
+		if len(group) > 0 {
+			pipeline = append(pipeline,
+				bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: group}}}},
+				bson.D{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$_id"}}}},
+			)
+		}
+	}
+
+	return append(pipeline, stages...)
+}
+
+// queryDistinct executes an aggregation that returns the unique documents of
+// the result set.
+func (r *resultQuery) queryDistinct(ctx context.Context) (*mongo.Cursor, error) {
+	var stages []bson.D
+
+	if len(r.sort) > 0 {
+		stages = append(stages, bson.D{{Key: "$sort", Value: r.sortFields()}})
+	}
+	if r.offset > 0 {
+		stages = append(stages, bson.D{{Key: "$skip", Value: int64(r.offset)}})
+	}
+	if r.limit > 0 {
+		stages = append(stages, bson.D{{Key: "$limit", Value: int64(r.limit)}})
+	}
+
+	return r.c.collection.Aggregate(ctx, r.pipeline(stages...))
+}
+
+// countDistinct counts the unique documents of the result set.
+func (r *resultQuery) countDistinct(ctx context.Context) (int64, error) {
+	cur, err := r.c.collection.Aggregate(ctx, r.pipeline(bson.D{{Key: "$count", Value: "n"}}))
+	if err != nil {
+		return 0, fmt.Errorf("Aggregate: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	if !cur.Next(ctx) {
+		return 0, cur.Err()
+	}
+
+	var counter struct {
+		N int64 `bson:"n"`
+	}
+	if err := cur.Decode(&counter); err != nil {
+		return 0, err
+	}
+
+	return counter.N, nil
+}
+
+// aggregate applies the given accumulator, like $sum or $avg, to the values
+// of a field and decodes the result into dst. dst is set to its zero value if
+// there are no values.
+func (r *resultQuery) aggregate(ctx context.Context, accumulator string, field string, dst interface{}) error {
+	dstV := reflect.ValueOf(dst)
+	if dstV.Kind() != reflect.Ptr || dstV.IsNil() {
+		return db.ErrUnsupportedDestination
+	}
+	if len(r.groupBy) > 0 {
+		return db.ErrUnsupported
+	}
+
+	group := bson.D{
+		{Key: "_id", Value: nil},
+		{Key: "v", Value: bson.D{{Key: accumulator, Value: "$" + field}}},
+	}
+
+	cur, err := r.c.collection.Aggregate(ctx, r.pipeline(bson.D{{Key: "$group", Value: group}}))
+	if err != nil {
+		return fmt.Errorf("Aggregate: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	dstV.Elem().Set(reflect.Zero(dstV.Elem().Type()))
+
+	// The pipeline produces no documents when nothing matches.
+	if !cur.Next(ctx) {
+		return cur.Err()
+	}
+
+	var value struct {
+		V bson.RawValue `bson:"v"`
+	}
+	if err := cur.Decode(&value); err != nil {
+		return err
+	}
+	if value.V.Type == 0 || value.V.Type == bson.TypeNull {
+		return nil
+	}
+
+	return value.V.Unmarshal(dst)
+}
+
 func (r *resultQuery) count() (int64, error) {
 	ctx := context.Background()
 
@@ -515,6 +916,10 @@ func (r *resultQuery) count() (int64, error) {
 		return 0, db.ErrUnsupported
 	}
 
+	if r.distinct {
+		return r.countDistinct(ctx)
+	}
+
 	n, err := r.c.collection.CountDocuments(ctx, r.conditions, opts)
 	if err != nil {
 		return 0, fmt.Errorf("CountDocuments: %w", err)
@@ -548,7 +953,7 @@ func (res *result) Count() (total uint64, err error) {
 	}
 
 	defer func(start time.Time) {
-		queryLog(&db.QueryStatus{
+		queryLog(rq.c.parent, &db.QueryStatus{
 			RawQuery: rq.debugQuery("Count"),
 			Err:      err,
 			Start:    start,
@@ -564,6 +969,163 @@ func (res *result) Count() (total uint64, err error) {
 	return uint64(count), nil
 }
 
+// Sum stores the sum of the values of the given field into dst.
+func (res *result) Sum(field string, dst interface{}) error {
+	return res.aggregate("$sum", field, dst)
+}
+
+// Avg returns the average of the values of the given field.
+func (res *result) Avg(field string) (float64, error) {
+	var avg float64
+	if err := res.aggregate("$avg", field, &avg); err != nil {
+		return 0, err
+	}
+	return avg, nil
+}
+
+// Min stores the smallest value of the given field into dst.
+func (res *result) Min(field string, dst interface{}) error {
+	return res.aggregate("$min", field, dst)
+}
+
+// Max stores the largest value of the given field into dst.
+func (res *result) Max(field string, dst interface{}) error {
+	return res.aggregate("$max", field, dst)
+}
+
+func (res *result) aggregate(accumulator string, field string, dst interface{}) (err error) {
+	rq, err := res.build()
+	if err != nil {
+		return err
+	}
+
+	defer func(start time.Time) {
+		queryLog(rq.c.parent, &db.QueryStatus{
+			RawQuery: rq.debugQuery(fmt.Sprintf("Aggregate.%s(%q)", accumulator, field)),
+			Err:      err,
+			Start:    start,
+			End:      time.Now(),
+		})
+	}(time.Now())
+
+	return rq.aggregate(context.Background(), accumulator, field, dst)
+}
+
+// Pluck dumps the values of the given field into a pointer to a slice.
+func (res *result) Pluck(field string, dst interface{}) (err error) {
+	rq, err := res.Select(field).(*result).build()
+	if err != nil {
+		return err
+	}
+
+	q, err := rq.query()
+	if err != nil {
+		return err
+	}
+
+	defer func(start time.Time) {
+		queryLog(rq.c.parent, &db.QueryStatus{
+			RawQuery: rq.debugQuery("Find.Pluck"),
+			Err:      err,
+			Start:    start,
+			End:      time.Now(),
+		})
+	}(time.Now())
+
+	err = decodeAll(context.Background(), q, dst, rq.strict())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return db.ErrNoMoreRows
+	}
+	return err
+}
+
+// Chunk walks the result set in chunks of at most size documents, paginated
+// by _id or by opts.Key, or in the order of the result set if it's sorted and
+// no key is given. Transactions are not supported.
+func (res *result) Chunk(ctx context.Context, size uint, fn db.ChunkFunc, opts *db.ChunkOptions) error {
+	if size == 0 {
+		return db.ErrInvalidChunkSize
+	}
+	if opts == nil {
+		opts = &db.ChunkOptions{}
+	}
+	if opts.Transaction {
+		return db.ErrNotSupportedByAdapter
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	batch := opts.Into
+	if batch == nil {
+		batch = &[]map[string]interface{}{}
+	}
+
+	rq, err := res.build()
+	if err != nil {
+		return err
+	}
+	if rq.offset > 0 || (opts.Key != "" && len(rq.sort) > 0) {
+		return db.ErrInvalidChunkQuery
+	}
+
+	key := opts.Key
+	if key == "" && len(rq.sort) == 0 {
+		key = "_id"
+	}
+
+	// The limit of the result set caps the number of documents that are
+	// processed.
+	remaining := rq.limit
+
+	var lastKey interface{}
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunkSize := int(size)
+		if remaining > 0 {
+			chunkSize = min(chunkSize, remaining)
+		}
+
+		var chunk db.Result
+		if key != "" {
+			chunk = res.Paginate(0).OrderBy(key).Limit(chunkSize)
+			if page > 1 {
+				chunk = chunk.And(db.Cond{key + " >": lastKey})
+			}
+		} else {
+			chunk = res.Paginate(0).Limit(chunkSize).Offset((page - 1) * int(size))
+		}
+		if err := chunk.All(batch); err != nil {
+			return err
+		}
+
+		n := reflect.ValueOf(batch).Elem().Len()
+		if n == 0 {
+			return nil
+		}
+		if key != "" {
+			if lastKey, err = chunkKey(batch, key); err != nil {
+				return err
+			}
+		}
+		if err := fn(rq.c.parent, batch); err != nil {
+			return err
+		}
+
+		if n < chunkSize {
+			return nil
+		}
+		if remaining > 0 {
+			if remaining -= n; remaining == 0 {
+				return nil
+			}
+		}
+	}
+}
+
 func (res *result) Prev() immutable.Immutable {
 	if res == nil {
 		return nil
@@ -606,6 +1168,9 @@ func (r *resultQuery) debugQuery(action string) string {
 			query = fmt.Sprintf("%s.select(%v)", query, selectedFields)
 		}
 	}
+	if r.distinct {
+		query = fmt.Sprintf("%s.distinct()", query)
+	}
 	if len(r.groupBy) > 0 {
 		escaped := make([]string, len(r.groupBy))
 		for i := range r.groupBy {
@@ -631,19 +1196,28 @@ func mustJSON(in interface{}) (out []byte) {
 	return out
 }
 
-func queryLog(status *db.QueryStatus) {
+func queryLog(settings db.Settings, status *db.QueryStatus) {
 	diff := status.End.Sub(status.Start)
 
 	slowQuery := false
-	if diff >= time.Millisecond*100 {
+	if threshold := settings.SlowQueryThreshold(); threshold > 0 && diff >= threshold {
 		status.Err = db.ErrWarnSlowQuery
 		slowQuery = true
 	}
 
+	if !settings.QueryArgsLoggingEnabled() {
+		status.Args = nil
+	}
+	status.SkipStack = !settings.QueryStackCaptureEnabled()
+
 	if status.Err != nil || slowQuery {
-		db.LC().Warn(status)
+		if settings.QueryLogLevel() <= db.LogLevelWarn {
+			db.LC().Warn(status)
+		}
 		return
 	}
 
-	db.LC().Debug(status)
+	if settings.QueryLogLevel() <= db.LogLevelDebug {
+		db.LC().Debug(status)
+	}
 }
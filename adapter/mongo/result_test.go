@@ -0,0 +1,74 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestUpdateDocument(t *testing.T) {
+	{
+		values := map[string]interface{}{"name": "Flea"}
+		update, err := updateDocument(values)
+		assert.NoError(t, err)
+		assert.Equal(t, bson.M{"$set": values}, update)
+	}
+
+	{
+		update, err := updateDocument(map[string]interface{}{
+			"synced_at": db.Column("updated_at"),
+			"name":      "$Flea",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"synced_at": "$updated_at",
+			"name":      bson.M{"$literal": "$Flea"},
+		}}}}, update)
+	}
+
+	{
+		_, err := updateDocument(map[string]interface{}{"views": db.Raw("views + 1")})
+		assert.ErrorIs(t, err, db.ErrNotSupportedByAdapter)
+	}
+}
+
+func TestNegate(t *testing.T) {
+	for in, out := range map[interface{}]interface{}{
+		3:          int64(-3),
+		int8(-2):   int64(2),
+		uint64(5):  int64(-5),
+		float32(1): float64(-1),
+		2.5:        -2.5,
+	} {
+		n, err := negate(in)
+		assert.NoError(t, err)
+		assert.Equal(t, out, n)
+	}
+
+	_, err := negate("1")
+	assert.ErrorIs(t, err, db.ErrUnsupportedValue)
+}
@@ -27,12 +27,15 @@ import (
 	"github.com/upper/db/v4/internal/sqlbuilder"
 )
 
+// collectionAdapter does not insert batches of rows: SQL Server does not
+// guarantee that OUTPUT returns rows in the order they were given in VALUES,
+// so the IDs could not be matched to their items.
 type collectionAdapter struct {
 	hasIdentityColumn *bool
 }
 
 func (adt *collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (interface{}, error) {
-	columnNames, columnValues, err := sqlbuilder.Map(item, nil)
+	columnNames, columnValues, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{Mapper: sqlbuilder.MapperOf(col.Session())})
 	if err != nil {
 		return nil, err
 	}
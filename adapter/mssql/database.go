@@ -26,6 +26,7 @@ package mssql
 
 import (
 	"strings"
+	"time"
 
 	"database/sql"
 
@@ -77,10 +78,17 @@ func (*database) Err(err error) error {
 		if strings.Contains(s, `many connections`) {
 			return db.ErrTooManyClients
 		}
+		return convertError(err)
 	}
 	return err
 }
 
+// NormalizeTime converts automatic timestamps to UTC, SQL Server datetime
+// columns don't store time zones.
+func (*database) NormalizeTime(t time.Time) time.Time {
+	return t.UTC()
+}
+
 func (*database) NewCollection() sqladapter.CollectionAdapter {
 	return &collectionAdapter{}
 }
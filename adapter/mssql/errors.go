@@ -0,0 +1,102 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mssql
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	mssqldriver "github.com/denisenkom/go-mssqldb"
+	db "github.com/upper/db/v4"
+)
+
+// SQL Server does not report constraint, table and column names as separate
+// fields, so they're extracted from the error message.
+var (
+	reConstraintName = regexp.MustCompile(`constraint ["']([^"']+)["']`)
+	reUniqueIndex    = regexp.MustCompile(`with unique index '([^']+)'`)
+	reObjectName     = regexp.MustCompile(`(?:in object|table) ["']([^"']+)["']`)
+	reColumnName     = regexp.MustCompile(`column '([^']+)'`)
+)
+
+// mssqlErrorReasons maps SQL Server error numbers to portable errors, see
+// https://learn.microsoft.com/en-us/sql/relational-databases/errors-events/database-engine-events-and-errors
+var mssqlErrorReasons = map[int32]error{
+	2627: db.ErrUniqueViolation,
+	2601: db.ErrUniqueViolation,
+	515:  db.ErrNotNullViolation,
+	1205: db.ErrDeadlock,
+	1222: db.ErrLockTimeout,
+}
+
+// convertError wraps driver errors that have a portable equivalent into a
+// *db.DatabaseError.
+func convertError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return &db.DatabaseError{
+			Reason: db.ErrQueryCanceled,
+			Err:    err,
+		}
+	}
+
+	var mssqlErr mssqldriver.Error
+	if !errors.As(err, &mssqlErr) {
+		return err
+	}
+
+	msg := mssqlErr.Message
+
+	reason, ok := mssqlErrorReasons[mssqlErr.Number]
+	if mssqlErr.Number == 547 {
+		// Foreign key and check violations share the same number.
+		switch {
+		case strings.Contains(msg, "FOREIGN KEY constraint"):
+			reason, ok = db.ErrForeignKeyViolation, true
+		case strings.Contains(msg, "CHECK constraint"):
+			reason, ok = db.ErrCheckViolation, true
+		}
+	}
+	if !ok {
+		return err
+	}
+
+	dbErr := &db.DatabaseError{
+		Reason: reason,
+		Err:    err,
+	}
+	if m := reConstraintName.FindStringSubmatch(msg); m != nil {
+		dbErr.Constraint = m[1]
+	} else if m := reUniqueIndex.FindStringSubmatch(msg); m != nil {
+		dbErr.Constraint = m[1]
+	}
+	if m := reObjectName.FindStringSubmatch(msg); m != nil {
+		// Objects are qualified, as in "dbo.users" or "test.dbo.users".
+		parts := strings.Split(m[1], ".")
+		dbErr.Table = parts[len(parts)-1]
+	}
+	if m := reColumnName.FindStringSubmatch(msg); m != nil {
+		dbErr.Column = m[1]
+	}
+	return dbErr
+}
@@ -0,0 +1,82 @@
+package mssql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mssqldriver "github.com/denisenkom/go-mssqldb"
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+func TestConvertError(t *testing.T) {
+	testCases := []struct {
+		err        mssqldriver.Error
+		reason     error
+		constraint string
+		table      string
+		column     string
+	}{
+		{
+			err:        mssqldriver.Error{Number: 2627, Message: "Violation of UNIQUE KEY constraint 'UQ_users_username'. Cannot insert duplicate key in object 'dbo.users'. The duplicate key value is (joe)."},
+			reason:     db.ErrUniqueViolation,
+			constraint: "UQ_users_username",
+			table:      "users",
+		},
+		{
+			err:        mssqldriver.Error{Number: 2601, Message: "Cannot insert duplicate key row in object 'dbo.users' with unique index 'ix_users_username'. The duplicate key value is (joe)."},
+			reason:     db.ErrUniqueViolation,
+			constraint: "ix_users_username",
+			table:      "users",
+		},
+		{
+			err:        mssqldriver.Error{Number: 547, Message: `The INSERT statement conflicted with the FOREIGN KEY constraint "FK_users_accounts". The conflict occurred in database "test", table "dbo.accounts", column 'id'.`},
+			reason:     db.ErrForeignKeyViolation,
+			constraint: "FK_users_accounts",
+			table:      "accounts",
+			column:     "id",
+		},
+		{
+			err:        mssqldriver.Error{Number: 547, Message: `The INSERT statement conflicted with the CHECK constraint "CK_users_username". The conflict occurred in database "test", table "dbo.users", column 'username'.`},
+			reason:     db.ErrCheckViolation,
+			constraint: "CK_users_username",
+			table:      "users",
+			column:     "username",
+		},
+		{
+			err:    mssqldriver.Error{Number: 515, Message: "Cannot insert the value NULL into column 'username', table 'test.dbo.users'; column does not allow nulls. INSERT fails."},
+			reason: db.ErrNotNullViolation,
+			table:  "users",
+			column: "username",
+		},
+		{
+			err:    mssqldriver.Error{Number: 1205, Message: "Transaction (Process ID 52) was deadlocked on lock resources with another process and has been chosen as the deadlock victim. Rerun the transaction."},
+			reason: db.ErrDeadlock,
+		},
+		{
+			err:    mssqldriver.Error{Number: 1222, Message: "Lock request time out period exceeded."},
+			reason: db.ErrLockTimeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := (&database{}).Err(tc.err)
+		assert.True(t, errors.Is(err, tc.reason), tc.err.Message)
+
+		var dbErr *db.DatabaseError
+		if assert.True(t, errors.As(err, &dbErr)) {
+			assert.Equal(t, tc.constraint, dbErr.Constraint)
+			assert.Equal(t, tc.table, dbErr.Table)
+			assert.Equal(t, tc.column, dbErr.Column)
+		}
+	}
+
+	err := (&database{}).Err(context.Canceled)
+	assert.True(t, errors.Is(err, db.ErrQueryCanceled))
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// Errors with no portable equivalent are returned as they are.
+	driverErr := mssqldriver.Error{Number: 208, Message: "Invalid object name 'users_x'."}
+	assert.Equal(t, error(driverErr), (&database{}).Err(driverErr))
+}
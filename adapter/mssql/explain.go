@@ -0,0 +1,219 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter"
+)
+
+// ExplainStatement describes the plan of the given query. SQL Server has no
+// EXPLAIN statement, instead SHOWPLAN_ALL (or STATISTICS PROFILE when
+// opts.Analyze is set) is enabled on the connection while the query runs. A
+// transaction is used to make sure the option and the query share the same
+// connection.
+func (*database) ExplainStatement(sess sqladapter.Session, ctx context.Context, query string, args []interface{}, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	option := "SHOWPLAN_ALL"
+	if opts.Analyze {
+		option = "STATISTICS PROFILE"
+	}
+
+	tx := sess
+	if !sess.IsTransaction() {
+		var err error
+		if tx, err = sess.NewTransaction(ctx, nil); err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = tx.Rollback()
+			_ = tx.Close()
+		}()
+	}
+
+	if _, err := tx.SQL().ExecContext(ctx, "SET "+option+" ON"); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = tx.SQL().ExecContext(ctx, "SET "+option+" OFF")
+	}()
+
+	rows, err := tx.SQL().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// With STATISTICS PROFILE the query results come first and the plan
+	// follows in a result set of its own.
+	for {
+		columns, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if hasColumn(columns, "NodeId") {
+			planRows, err := scanQueryPlanRows(rows, columns)
+			if err != nil {
+				return nil, err
+			}
+			return newQueryPlan(columns, planRows), nil
+		}
+		for rows.Next() {
+			// Discard query results.
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, db.ErrNotSupportedByAdapter
+}
+
+func hasColumn(columns []string, name string) bool {
+	for i := range columns {
+		if columns[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func scanQueryPlanRows(rows *sql.Rows, columns []string) ([]map[string]interface{}, error) {
+	planRows := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i := range columns {
+			row[columns[i]] = values[i]
+		}
+		planRows = append(planRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return planRows, nil
+}
+
+// newQueryPlan builds a plan tree from SHOWPLAN_ALL or STATISTICS PROFILE
+// rows, each row references its parent by NodeId. Rows describing the
+// statement itself (with no physical operator) become root nodes.
+func newQueryPlan(columns []string, rows []map[string]interface{}) *db.QueryPlan {
+	plan := &db.QueryPlan{}
+
+	raw := make([]string, 0, len(rows)+1)
+	raw = append(raw, strings.Join(columns, "\t"))
+
+	nodes := map[int64]*db.QueryPlanNode{}
+	var statement *db.QueryPlanNode
+
+	for _, row := range rows {
+		values := make([]string, len(columns))
+		for i := range columns {
+			if row[columns[i]] != nil {
+				values[i] = fmt.Sprintf("%v", row[columns[i]])
+			}
+		}
+		raw = append(raw, strings.Join(values, "\t"))
+
+		node := &db.QueryPlanNode{
+			Properties: map[string]interface{}{},
+		}
+		for key, value := range row {
+			switch key {
+			case "PhysicalOp":
+				node.Operation = strings.TrimSpace(asString(value))
+			case "NodeId", "Parent":
+			default:
+				if value != nil {
+					node.Properties[key] = value
+				}
+			}
+		}
+
+		if node.Operation == "" {
+			// The statement itself.
+			node.Operation = strings.TrimSpace(asString(row["Type"]))
+			statement = node
+			plan.Nodes = append(plan.Nodes, node)
+			continue
+		}
+
+		// Table access looks like "OBJECT:([db].[dbo].[artist].[PK_artist])".
+		if argument := asString(row["Argument"]); strings.HasPrefix(argument, "OBJECT:(") {
+			parts := strings.Split(strings.TrimPrefix(argument, "OBJECT:("), ".")
+			if len(parts) >= 3 {
+				node.Table = strings.Trim(strings.TrimRight(parts[2], ")"), "[]")
+			}
+		}
+
+		id, parentID := asInt64(row["NodeId"]), asInt64(row["Parent"])
+		nodes[id] = node
+		if parent, ok := nodes[parentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else if statement != nil {
+			statement.Children = append(statement.Children, node)
+		} else {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+	}
+
+	plan.Raw = strings.Join(raw, "\n")
+	return plan
+}
+
+func asString(in interface{}) string {
+	switch v := in.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%v", in)
+}
+
+func asInt64(in interface{}) int64 {
+	switch v := in.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	}
+	return -1
+}
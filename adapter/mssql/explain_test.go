@@ -0,0 +1,63 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueryPlan(t *testing.T) {
+	columns := []string{"StmtText", "NodeId", "Parent", "PhysicalOp", "LogicalOp", "Argument", "EstimateRows", "Type"}
+	rows := []map[string]interface{}{
+		{
+			"StmtText":     "SELECT TOP 1 * FROM [artist] WHERE [name] = @p1",
+			"NodeId":       int32(1),
+			"Parent":       int32(0),
+			"PhysicalOp":   nil,
+			"LogicalOp":    nil,
+			"Argument":     nil,
+			"EstimateRows": 1.0,
+			"Type":         "SELECT",
+		},
+		{
+			"StmtText":     "|--Top(TOP EXPRESSION:((1)))",
+			"NodeId":       int32(2),
+			"Parent":       int32(1),
+			"PhysicalOp":   "Top",
+			"LogicalOp":    "Top",
+			"Argument":     "TOP EXPRESSION:((1))",
+			"EstimateRows": 1.0,
+			"Type":         "PLAN_ROW",
+		},
+		{
+			"StmtText":     "|--Clustered Index Scan(OBJECT:([test].[dbo].[artist].[PK_artist]))",
+			"NodeId":       int32(3),
+			"Parent":       int32(2),
+			"PhysicalOp":   "Clustered Index Scan",
+			"LogicalOp":    "Clustered Index Scan",
+			"Argument":     "OBJECT:([test].[dbo].[artist].[PK_artist]), WHERE:([name]=[@p1])",
+			"EstimateRows": 1.0,
+			"Type":         "PLAN_ROW",
+		},
+	}
+
+	plan := newQueryPlan(columns, rows)
+	assert.Contains(t, plan.Raw, "StmtText\tNodeId\tParent")
+	assert.Contains(t, plan.Raw, "Clustered Index Scan")
+
+	assert.Equal(t, 1, len(plan.Nodes))
+
+	statement := plan.Nodes[0]
+	assert.Equal(t, "SELECT", statement.Operation)
+
+	assert.Equal(t, 1, len(statement.Children))
+	top := statement.Children[0]
+	assert.Equal(t, "Top", top.Operation)
+	assert.Equal(t, "", top.Table)
+
+	assert.Equal(t, 1, len(top.Children))
+	scan := top.Children[0]
+	assert.Equal(t, "Clustered Index Scan", scan.Operation)
+	assert.Equal(t, "artist", scan.Table)
+	assert.Equal(t, 1.0, scan.Properties["EstimateRows"])
+}
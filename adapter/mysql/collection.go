@@ -22,6 +22,8 @@
 package mysql
 
 import (
+	"fmt"
+
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/sqladapter"
 	"github.com/upper/db/v4/internal/sqlbuilder"
@@ -31,7 +33,7 @@ type collectionAdapter struct {
 }
 
 func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (interface{}, error) {
-	columnNames, columnValues, err := sqlbuilder.Map(item, nil)
+	columnNames, columnValues, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{Mapper: sqlbuilder.MapperOf(col.Session())})
 	if err != nil {
 		return nil, err
 	}
@@ -75,3 +77,51 @@ func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (i
 
 	return keyMap, nil
 }
+
+func (*collectionAdapter) InsertBatch(col sqladapter.Collection, columns []string, rows [][]interface{}) ([]interface{}, error) {
+	q := col.SQL().InsertInto(col.Name()).Columns(columns...)
+	for i := range rows {
+		q = q.Values(rows[i]...)
+	}
+
+	res, err := q.Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	// LastInsertId is the ID of the first row, the rest of the rows of a
+	// multi-row insert get consecutive IDs that are auto_increment_increment
+	// apart. Batches are inserted within a transaction, which keeps this query
+	// on the connection that ran the insert.
+	firstID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	if firstID == 0 {
+		// The primary key is not an AUTO_INCREMENT column, there's no way to
+		// know the IDs of the new rows.
+		return nil, fmt.Errorf("upper: can't get the IDs of the rows inserted into %q, its primary key is not AUTO_INCREMENT", col.Name())
+	}
+
+	var increment int64
+	row, err := col.SQL().QueryRow("SELECT @@SESSION.auto_increment_increment")
+	if err != nil {
+		return nil, err
+	}
+	if err := row.Scan(&increment); err != nil {
+		return nil, err
+	}
+
+	ids := make([]interface{}, len(rows))
+	for i := range rows {
+		ids[i] = firstID + int64(i)*increment
+	}
+	return ids, nil
+}
+
+// MaxPlaceholders returns the maximum number of parameters a prepared
+// statement can have.
+func (*collectionAdapter) MaxPlaceholders() int {
+	return 65535
+}
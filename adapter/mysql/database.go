@@ -27,6 +27,7 @@ package mysql
 import (
 	"reflect"
 	"strings"
+	"time"
 
 	"database/sql"
 
@@ -109,10 +110,18 @@ func (*database) Err(err error) error {
 		if strings.Contains(s, `many connections`) {
 			return db.ErrTooManyClients
 		}
+		return convertError(err)
 	}
 	return err
 }
 
+// NormalizeTime converts automatic timestamps to UTC, which is the location
+// the driver uses by default, and truncates them to the microsecond
+// precision MySQL stores timestamps with.
+func (*database) NormalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
 func (*database) NewCollection() sqladapter.CollectionAdapter {
 	return &collectionAdapter{}
 }
@@ -0,0 +1,100 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mysql
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	db "github.com/upper/db/v4"
+)
+
+// MySQL does not report constraint, table and column names as separate
+// fields, so they're extracted from the error message.
+var (
+	reDuplicateEntry  = regexp.MustCompile(`for key '([^']+)'`)
+	reForeignKey      = regexp.MustCompile("`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`\\)")
+	reQuotedColumn    = regexp.MustCompile(`^(?:Column|Field) '([^']+)'`)
+	reCheckConstraint = regexp.MustCompile(`^Check constraint '([^']+)'`)
+)
+
+// mysqlErrorReasons maps MySQL error numbers to portable errors, see
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+var mysqlErrorReasons = map[uint16]error{
+	1062: db.ErrUniqueViolation,
+	1451: db.ErrForeignKeyViolation,
+	1452: db.ErrForeignKeyViolation,
+	1048: db.ErrNotNullViolation,
+	1364: db.ErrNotNullViolation,
+	3819: db.ErrCheckViolation,
+	1213: db.ErrDeadlock,
+	1205: db.ErrLockTimeout,
+	1317: db.ErrQueryCanceled,
+	3024: db.ErrQueryCanceled,
+}
+
+// convertError wraps driver errors that have a portable equivalent into a
+// *db.DatabaseError.
+func convertError(err error) error {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+	reason, ok := mysqlErrorReasons[mysqlErr.Number]
+	if !ok {
+		return err
+	}
+
+	dbErr := &db.DatabaseError{
+		Reason: reason,
+		Err:    err,
+	}
+
+	msg := mysqlErr.Message
+	switch reason {
+	case db.ErrUniqueViolation:
+		if m := reDuplicateEntry.FindStringSubmatch(msg); m != nil {
+			// MySQL 8 prefixes the key with the name of the table.
+			if table, key, ok := strings.Cut(m[1], "."); ok {
+				dbErr.Table, dbErr.Constraint = table, key
+			} else {
+				dbErr.Constraint = m[1]
+			}
+		}
+	case db.ErrForeignKeyViolation:
+		if m := reForeignKey.FindStringSubmatch(msg); m != nil {
+			dbErr.Table, dbErr.Constraint, dbErr.Column = m[1], m[2], m[3]
+		}
+	case db.ErrNotNullViolation:
+		if m := reQuotedColumn.FindStringSubmatch(msg); m != nil {
+			dbErr.Column = m[1]
+		}
+	case db.ErrCheckViolation:
+		if m := reCheckConstraint.FindStringSubmatch(msg); m != nil {
+			dbErr.Constraint = m[1]
+		}
+	}
+
+	return dbErr
+}
@@ -0,0 +1,78 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+func TestConvertError(t *testing.T) {
+	testCases := []struct {
+		err        *mysqldriver.MySQLError
+		reason     error
+		constraint string
+		table      string
+		column     string
+	}{
+		{
+			err:        &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'joe' for key 'users.username'"},
+			reason:     db.ErrUniqueViolation,
+			constraint: "username",
+			table:      "users",
+		},
+		{
+			err:        &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'joe' for key 'username'"},
+			reason:     db.ErrUniqueViolation,
+			constraint: "username",
+		},
+		{
+			err:        &mysqldriver.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`test`.`users`, CONSTRAINT `users_ibfk_1` FOREIGN KEY (`account_id`) REFERENCES `accounts` (`id`))"},
+			reason:     db.ErrForeignKeyViolation,
+			constraint: "users_ibfk_1",
+			table:      "users",
+			column:     "account_id",
+		},
+		{
+			err:    &mysqldriver.MySQLError{Number: 1048, Message: "Column 'username' cannot be null"},
+			reason: db.ErrNotNullViolation,
+			column: "username",
+		},
+		{
+			err:        &mysqldriver.MySQLError{Number: 3819, Message: "Check constraint 'users_chk_1' is violated."},
+			reason:     db.ErrCheckViolation,
+			constraint: "users_chk_1",
+		},
+		{
+			err:    &mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"},
+			reason: db.ErrDeadlock,
+		},
+		{
+			err:    &mysqldriver.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"},
+			reason: db.ErrLockTimeout,
+		},
+		{
+			err:    &mysqldriver.MySQLError{Number: 1317, Message: "Query execution was interrupted"},
+			reason: db.ErrQueryCanceled,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := (&database{}).Err(tc.err)
+		assert.True(t, errors.Is(err, tc.reason), tc.err.Message)
+		assert.True(t, errors.Is(err, tc.err), tc.err.Message)
+
+		var dbErr *db.DatabaseError
+		if assert.True(t, errors.As(err, &dbErr)) {
+			assert.Equal(t, tc.constraint, dbErr.Constraint)
+			assert.Equal(t, tc.table, dbErr.Table)
+			assert.Equal(t, tc.column, dbErr.Column)
+		}
+	}
+
+	// Errors with no portable equivalent are returned as they are.
+	driverErr := &mysqldriver.MySQLError{Number: 1146, Message: "Table 'test.users_x' doesn't exist"}
+	assert.Equal(t, error(driverErr), (&database{}).Err(driverErr))
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter"
+)
+
+var (
+	reAnalyzeTable   = regexp.MustCompile(` on ([^\s]+)`)
+	reAnalyzeDetails = regexp.MustCompile(`\(([^()]*)\)`)
+)
+
+// ExplainStatement runs EXPLAIN on the given query. Plans are requested in
+// JSON format, except for EXPLAIN ANALYZE which is only available as a tree.
+func (*database) ExplainStatement(sess sqladapter.Session, ctx context.Context, query string, args []interface{}, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	explain := "EXPLAIN FORMAT=JSON "
+	if opts.Analyze {
+		explain = "EXPLAIN ANALYZE "
+	}
+
+	rows, err := sess.SQL().QueryContext(ctx, explain+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var raw string
+	for rows.Next() {
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.Analyze {
+		return newAnalyzeQueryPlan(raw), nil
+	}
+	return newQueryPlan(raw)
+}
+
+// newQueryPlan parses the output of EXPLAIN FORMAT=JSON. Every object within
+// the plan, like "query_block", "nested_loop" or "table", is an operation and
+// its scalar values are properties of it.
+func newQueryPlan(raw string) (*db.QueryPlan, error) {
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		return nil, fmt.Errorf("could not parse query plan: %w", err)
+	}
+
+	plan := &db.QueryPlan{Raw: raw}
+	for _, key := range sortedKeys(out) {
+		if node := newQueryPlanNode(key, out[key]); node != nil {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+	}
+	return plan, nil
+}
+
+func newQueryPlanNode(operation string, in interface{}) *db.QueryPlanNode {
+	node := &db.QueryPlanNode{
+		Operation:  operation,
+		Properties: map[string]interface{}{},
+	}
+
+	switch v := in.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			value := v[key]
+			switch {
+			case key == "table_name":
+				node.Table, _ = value.(string)
+			case strings.HasSuffix(key, "_info"):
+				// Cost and other informative objects are properties.
+				node.Properties[key] = value
+			default:
+				if child := newQueryPlanNode(key, value); child != nil {
+					node.Children = append(node.Children, child)
+				} else {
+					node.Properties[key] = value
+				}
+			}
+		}
+	case []interface{}:
+		// Lists, like "nested_loop", hold objects with operations.
+		for i := range v {
+			item, ok := v[i].(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			for _, key := range sortedKeys(item) {
+				if child := newQueryPlanNode(key, item[key]); child != nil {
+					node.Children = append(node.Children, child)
+				}
+			}
+		}
+	default:
+		return nil
+	}
+
+	return node
+}
+
+// newAnalyzeQueryPlan parses the output of EXPLAIN ANALYZE, which looks like:
+//
+//	-> Limit: 1 row(s)  (cost=0.35 rows=1) (actual time=0.02..0.02 rows=1 loops=1)
+//	    -> Table scan on artist  (cost=0.35 rows=1) (actual time=0.02..0.02 rows=1 loops=1)
+//
+// Nodes are nested by the column their arrow is at.
+func newAnalyzeQueryPlan(raw string) *db.QueryPlan {
+	plan := &db.QueryPlan{Raw: raw}
+
+	type level struct {
+		column int
+		node   *db.QueryPlanNode
+	}
+	stack := []level{}
+
+	for _, line := range strings.Split(raw, "\n") {
+		column := strings.Index(line, "-> ")
+		if column < 0 {
+			continue
+		}
+
+		text := line[column+len("-> "):]
+		node := &db.QueryPlanNode{
+			Properties: map[string]interface{}{},
+		}
+
+		operation, details, _ := strings.Cut(text, "  (")
+		node.Operation = strings.TrimSpace(operation)
+		if m := reAnalyzeTable.FindStringSubmatch(node.Operation); m != nil {
+			node.Table = strings.Trim(m[1], "`")
+		}
+		for _, group := range reAnalyzeDetails.FindAllStringSubmatch("("+details, -1) {
+			prefix, fields := "", group[1]
+			if strings.HasPrefix(fields, "actual ") {
+				prefix, fields = "actual ", strings.TrimPrefix(fields, "actual ")
+			}
+			for _, field := range strings.Fields(fields) {
+				if key, value, ok := strings.Cut(field, "="); ok {
+					node.Properties[prefix+key] = value
+				}
+			}
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].column >= column {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, node)
+		} else {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+		stack = append(stack, level{column: column, node: node})
+	}
+
+	return plan
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
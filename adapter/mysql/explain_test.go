@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueryPlan(t *testing.T) {
+	raw := `{
+		"query_block": {
+			"select_id": 1,
+			"cost_info": {"query_cost": "1.20"},
+			"nested_loop": [
+				{
+					"table": {
+						"table_name": "artist",
+						"access_type": "ALL",
+						"rows_examined_per_scan": 1
+					}
+				},
+				{
+					"table": {
+						"table_name": "publication",
+						"access_type": "ref",
+						"key": "author_id"
+					}
+				}
+			]
+		}
+	}`
+
+	plan, err := newQueryPlan(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, plan.Raw)
+
+	assert.Equal(t, 1, len(plan.Nodes))
+
+	block := plan.Nodes[0]
+	assert.Equal(t, "query_block", block.Operation)
+	assert.Equal(t, float64(1), block.Properties["select_id"])
+	assert.Equal(t, map[string]interface{}{"query_cost": "1.20"}, block.Properties["cost_info"])
+
+	assert.Equal(t, 1, len(block.Children))
+	loop := block.Children[0]
+	assert.Equal(t, "nested_loop", loop.Operation)
+
+	assert.Equal(t, 2, len(loop.Children))
+	assert.Equal(t, "table", loop.Children[0].Operation)
+	assert.Equal(t, "artist", loop.Children[0].Table)
+	assert.Equal(t, "ALL", loop.Children[0].Properties["access_type"])
+	assert.Equal(t, "publication", loop.Children[1].Table)
+	assert.Equal(t, "author_id", loop.Children[1].Properties["key"])
+
+	_, err = newQueryPlan("not json")
+	assert.Error(t, err)
+}
+
+func TestNewAnalyzeQueryPlan(t *testing.T) {
+	raw := "-> Limit: 1 row(s)  (cost=0.35 rows=1) (actual time=0.020..0.021 rows=1 loops=1)\n" +
+		"    -> Filter: (artist.`name` = 'Ozzie')  (cost=0.35 rows=1) (actual time=0.019..0.019 rows=1 loops=1)\n" +
+		"        -> Table scan on artist  (cost=0.35 rows=1) (actual time=0.017..0.017 rows=1 loops=1)\n"
+
+	plan := newAnalyzeQueryPlan(raw)
+	assert.Equal(t, raw, plan.Raw)
+
+	assert.Equal(t, 1, len(plan.Nodes))
+
+	limit := plan.Nodes[0]
+	assert.Equal(t, "Limit: 1 row(s)", limit.Operation)
+	assert.Equal(t, "0.35", limit.Properties["cost"])
+	assert.Equal(t, "1", limit.Properties["rows"])
+	assert.Equal(t, "0.020..0.021", limit.Properties["actual time"])
+	assert.Equal(t, "1", limit.Properties["actual loops"])
+
+	assert.Equal(t, 1, len(limit.Children))
+	filter := limit.Children[0]
+	assert.Equal(t, "Filter: (artist.`name` = 'Ozzie')", filter.Operation)
+	assert.Equal(t, "", filter.Table)
+
+	assert.Equal(t, 1, len(filter.Children))
+	scan := filter.Children[0]
+	assert.Equal(t, "Table scan on artist", scan.Operation)
+	assert.Equal(t, "artist", scan.Table)
+	assert.Empty(t, scan.Children)
+}
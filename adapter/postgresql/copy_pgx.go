@@ -0,0 +1,109 @@
+//go:build !pq
+// +build !pq
+
+package postgresql
+
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/upper/db/v4/internal/sqladapter"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// maxCopyParams is the number of placeholders a single INSERT statement of
+// copyFromTx can have, PostgreSQL doesn't accept more than 65535.
+const maxCopyParams = 65535
+
+func copyFrom(ctx context.Context, sess sqladapter.Session, identifier []string, src *sqlbuilder.CopySource) (int64, error) {
+	if sess.IsTransaction() {
+		return copyFromTx(ctx, sess, identifier, src)
+	}
+
+	// All the rows must be sent through the same connection.
+	conn, err := sess.DB().Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var n int64
+	err = conn.Raw(func(driverConn interface{}) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("upper: expecting a pgx connection but got %T", driverConn)
+		}
+		var err error
+		n, err = pgxConn.Conn().CopyFrom(ctx, pgx.Identifier(identifier), src.Columns(), src)
+		return err
+	})
+	return n, err
+}
+
+// copyFromTx loads rows within the transaction of sess. The connection of a
+// *sql.Tx can't be reached through database/sql, so instead of COPY the rows
+// are written with multi-row INSERT statements.
+func copyFromTx(ctx context.Context, sess sqladapter.Session, identifier []string, src *sqlbuilder.CopySource) (int64, error) {
+	table := strings.Join(identifier, ".")
+	columns := src.Columns()
+	batchSize := maxCopyParams / len(columns)
+
+	var n, pending int64
+	ins := sess.SQL().InsertInto(table).Columns(columns...)
+	flush := func() error {
+		if pending == 0 {
+			return nil
+		}
+		if _, err := ins.ExecContext(ctx); err != nil {
+			return err
+		}
+		n += pending
+		pending = 0
+		ins = sess.SQL().InsertInto(table).Columns(columns...)
+		return nil
+	}
+
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			return n, err
+		}
+		ins = ins.Values(values...)
+		if pending++; pending >= int64(batchSize) {
+			if err := flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	if err := src.Err(); err != nil {
+		return n, err
+	}
+	if err := flush(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,80 @@
+//go:build pq
+// +build pq
+
+package postgresql
+
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/upper/db/v4/internal/sqladapter"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+func copyFrom(ctx context.Context, sess sqladapter.Session, identifier []string, src *sqlbuilder.CopySource) (int64, error) {
+	query := pq.CopyIn(identifier[0], src.Columns()...)
+	if len(identifier) > 1 {
+		query = pq.CopyInSchema(identifier[0], identifier[1], src.Columns()...)
+	}
+
+	var stmt *sql.Stmt
+	var err error
+	if tx := sess.Transaction(); tx != nil {
+		stmt, err = tx.PrepareContext(ctx, query)
+	} else {
+		// All the rows must be sent through the same connection.
+		var conn *sql.Conn
+		if conn, err = sess.DB().Conn(ctx); err != nil {
+			return 0, err
+		}
+		defer conn.Close()
+		stmt, err = conn.PrepareContext(ctx, query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var n int64
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := src.Err(); err != nil {
+		return 0, err
+	}
+
+	// Rows are buffered until the statement is executed without arguments.
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -27,6 +27,7 @@ package postgresql
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/sqladapter"
@@ -110,10 +111,17 @@ func (*database) Err(err error) error {
 		if strings.Contains(s, `too many clients`) || strings.Contains(s, `remaining connection slots are reserved`) || strings.Contains(s, `too many open`) {
 			return db.ErrTooManyClients
 		}
+		return convertError(err)
 	}
 	return err
 }
 
+// NormalizeTime converts automatic timestamps to UTC and truncates them to
+// the microsecond precision PostgreSQL stores timestamps with.
+func (*database) NormalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
 func (*database) NewCollection() sqladapter.CollectionAdapter {
 	return &collectionAdapter{}
 }
@@ -0,0 +1,62 @@
+//go:build !pq
+// +build !pq
+
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+func TestConvertError(t *testing.T) {
+	driverErr := &pgconn.PgError{Code: "23505", ConstraintName: "users_username_key", TableName: "users", Message: "duplicate key value violates unique constraint"}
+
+	err := (&database{}).Err(fmt.Errorf("insert: %w", driverErr))
+	assert.True(t, errors.Is(err, db.ErrUniqueViolation))
+	assert.True(t, errors.Is(err, driverErr))
+
+	var dbErr *db.DatabaseError
+	if assert.True(t, errors.As(err, &dbErr)) {
+		assert.Equal(t, "users_username_key", dbErr.Constraint)
+		assert.Equal(t, "users", dbErr.Table)
+	}
+
+	err = (&database{}).Err(&pgconn.PgError{Code: "23502", TableName: "users", ColumnName: "username"})
+	if assert.True(t, errors.As(err, &dbErr)) {
+		assert.Equal(t, db.ErrNotNullViolation, dbErr.Reason)
+		assert.Equal(t, "username", dbErr.Column)
+	}
+
+	err = (&database{}).Err(&pgconn.PgError{Code: "40P01"})
+	assert.True(t, errors.Is(err, db.ErrDeadlock))
+
+	// Errors with no portable equivalent are returned as they are.
+	driverErr = &pgconn.PgError{Code: "42P01"}
+	assert.Equal(t, error(driverErr), (&database{}).Err(driverErr))
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter"
+)
+
+// ExplainStatement runs EXPLAIN on the given query and returns its plan in
+// JSON format.
+func (*database) ExplainStatement(sess sqladapter.Session, ctx context.Context, query string, args []interface{}, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	format := "FORMAT JSON"
+	if opts.Analyze {
+		format = "ANALYZE, " + format
+	}
+
+	rows, err := sess.SQL().QueryContext(ctx, fmt.Sprintf("EXPLAIN (%s) %s", format, query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var raw string
+	for rows.Next() {
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return newQueryPlan(raw)
+}
+
+// newQueryPlan parses the output of EXPLAIN (FORMAT JSON).
+func newQueryPlan(raw string) (*db.QueryPlan, error) {
+	var out []map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		return nil, fmt.Errorf("could not parse query plan: %w", err)
+	}
+
+	plan := &db.QueryPlan{Raw: raw}
+	for i := range out {
+		root, ok := out[i]["Plan"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		node := newQueryPlanNode(root)
+		// Planning and execution times are reported next to the plan, not
+		// within it.
+		for key, value := range out[i] {
+			if key != "Plan" {
+				node.Properties[key] = value
+			}
+		}
+		plan.Nodes = append(plan.Nodes, node)
+	}
+
+	return plan, nil
+}
+
+func newQueryPlanNode(in map[string]interface{}) *db.QueryPlanNode {
+	node := &db.QueryPlanNode{
+		Properties: map[string]interface{}{},
+	}
+	for key, value := range in {
+		switch key {
+		case "Node Type":
+			node.Operation, _ = value.(string)
+		case "Relation Name":
+			node.Table, _ = value.(string)
+		case "Plans":
+			children, _ := value.([]interface{})
+			for i := range children {
+				if child, ok := children[i].(map[string]interface{}); ok {
+					node.Children = append(node.Children, newQueryPlanNode(child))
+				}
+			}
+		default:
+			node.Properties[key] = value
+		}
+	}
+	return node
+}
@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueryPlan(t *testing.T) {
+	raw := `[
+		{
+			"Plan": {
+				"Node Type": "Limit",
+				"Total Cost": 0.15,
+				"Plans": [
+					{
+						"Node Type": "Index Scan",
+						"Parent Relationship": "Outer",
+						"Relation Name": "artist",
+						"Alias": "artist",
+						"Index Name": "artist_pkey",
+						"Plan Rows": 1
+					}
+				]
+			},
+			"Planning Time": 0.05,
+			"Execution Time": 0.02
+		}
+	]`
+
+	plan, err := newQueryPlan(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, plan.Raw)
+
+	assert.Equal(t, 1, len(plan.Nodes))
+
+	limit := plan.Nodes[0]
+	assert.Equal(t, "Limit", limit.Operation)
+	assert.Equal(t, "", limit.Table)
+	assert.Equal(t, 0.15, limit.Properties["Total Cost"])
+	assert.Equal(t, 0.02, limit.Properties["Execution Time"])
+
+	assert.Equal(t, 1, len(limit.Children))
+	scan := limit.Children[0]
+	assert.Equal(t, "Index Scan", scan.Operation)
+	assert.Equal(t, "artist", scan.Table)
+	assert.Equal(t, "artist_pkey", scan.Properties["Index Name"])
+
+	_, err = newQueryPlan("not json")
+	assert.Error(t, err)
+}
@@ -81,7 +81,7 @@ func (*collectionAdapter) Find(col sqladapter.Collection, res *sqladapter.Result
 }
 
 func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (interface{}, error) {
-	columnNames, columnValues, err := sqlbuilder.Map(item, nil)
+	columnNames, columnValues, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{Mapper: sqlbuilder.MapperOf(col.Session())})
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +28,7 @@ import (
 	"context"
 	"database/sql"
 	"strings"
+	"time"
 
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/sqladapter"
@@ -106,6 +107,11 @@ func (*database) StatementExec(sess sqladapter.Session, ctx context.Context, que
 	return res, err
 }
 
+// NormalizeTime converts automatic timestamps to UTC.
+func (*database) NormalizeTime(t time.Time) time.Time {
+	return t.UTC()
+}
+
 func (*database) NewCollection() sqladapter.CollectionAdapter {
 	return &collectionAdapter{}
 }
@@ -150,7 +150,10 @@ func (h *Helper) SetUp() error {
 			`CREATE TABLE accounts (
 				name string,
 				disabled bool,
-				created_at time
+				created_at time,
+				deleted_at time,
+				version int,
+				updated_at time
 			)`,
 
 			`DROP TABLE IF EXISTS users`,
@@ -33,7 +33,7 @@ type collectionAdapter struct {
 }
 
 func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (interface{}, error) {
-	columnNames, columnValues, err := sqlbuilder.Map(item, nil)
+	columnNames, columnValues, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{Mapper: sqlbuilder.MapperOf(col.Session())})
 	if err != nil {
 		return nil, err
 	}
@@ -68,3 +68,34 @@ func (*collectionAdapter) Insert(col sqladapter.Collection, item interface{}) (i
 
 	return keyMap, nil
 }
+
+func (*collectionAdapter) InsertBatch(col sqladapter.Collection, columns []string, rows [][]interface{}) ([]interface{}, error) {
+	q := col.SQL().InsertInto(col.Name()).Columns(columns...)
+	for i := range rows {
+		q = q.Values(rows[i]...)
+	}
+
+	res, err := q.Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	// Writes are serialized, so the rows of a single statement get consecutive
+	// IDs that end with the last inserted one.
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]interface{}, len(rows))
+	for i := range rows {
+		ids[i] = lastID - int64(len(rows)-1-i)
+	}
+	return ids, nil
+}
+
+// MaxPlaceholders returns the default SQLITE_MAX_VARIABLE_NUMBER of SQLite
+// versions prior to 3.32.0, which may be linked instead of the bundled one.
+func (*collectionAdapter) MaxPlaceholders() int {
+	return 999
+}
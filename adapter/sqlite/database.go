@@ -28,6 +28,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite3 driver.
 	db "github.com/upper/db/v4"
@@ -93,6 +94,19 @@ func (*database) StatementExec(sess sqladapter.Session, ctx context.Context, que
 	return res, err
 }
 
+func (*database) Err(err error) error {
+	if err != nil {
+		return convertError(err)
+	}
+	return err
+}
+
+// NormalizeTime converts automatic timestamps to UTC, SQLite stores
+// timestamps as text without time zones.
+func (*database) NormalizeTime(t time.Time) time.Time {
+	return t.UTC()
+}
+
 func (*database) NewCollection() sqladapter.CollectionAdapter {
 	return &collectionAdapter{}
 }
@@ -0,0 +1,89 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sqlite
+
+import (
+	"errors"
+	"strings"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+	db "github.com/upper/db/v4"
+)
+
+// convertError wraps driver errors that have a portable equivalent into a
+// *db.DatabaseError.
+func convertError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	var reason error
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		reason = db.ErrUniqueViolation
+	case sqlite3.ErrConstraintForeignKey:
+		reason = db.ErrForeignKeyViolation
+	case sqlite3.ErrConstraintNotNull:
+		reason = db.ErrNotNullViolation
+	case sqlite3.ErrConstraintCheck:
+		reason = db.ErrCheckViolation
+	}
+	switch sqliteErr.Code {
+	case sqlite3.ErrBusy:
+		reason = db.ErrLockTimeout
+	case sqlite3.ErrInterrupt:
+		reason = db.ErrQueryCanceled
+	}
+	if reason == nil {
+		return err
+	}
+
+	dbErr := &db.DatabaseError{
+		Reason: reason,
+		Err:    err,
+	}
+	setConstraintDetails(dbErr, sqliteErr.Error())
+	return dbErr
+}
+
+// setConstraintDetails extracts table, column and constraint names from
+// messages like "UNIQUE constraint failed: users.username" or "CHECK
+// constraint failed: username_length".
+func setConstraintDetails(dbErr *db.DatabaseError, msg string) {
+	_, details, ok := strings.Cut(msg, "constraint failed: ")
+	if !ok {
+		return
+	}
+
+	switch dbErr.Reason {
+	case db.ErrUniqueViolation, db.ErrNotNullViolation:
+		// Compound keys are reported as "users.a, users.b", only the first
+		// column is kept.
+		first, _, _ := strings.Cut(details, ", ")
+		if table, column, ok := strings.Cut(first, "."); ok {
+			dbErr.Table, dbErr.Column = table, column
+		}
+	case db.ErrCheckViolation:
+		dbErr.Constraint = details
+	}
+}
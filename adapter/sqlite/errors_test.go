@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+func TestConvertError(t *testing.T) {
+	driverErr := sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}
+
+	err := (&database{}).Err(driverErr)
+	assert.True(t, errors.Is(err, db.ErrUniqueViolation))
+
+	var dbErr *db.DatabaseError
+	assert.True(t, errors.As(err, &dbErr))
+
+	err = (&database{}).Err(sqlite3.Error{Code: sqlite3.ErrBusy})
+	assert.True(t, errors.Is(err, db.ErrLockTimeout))
+
+	// Errors with no portable equivalent are returned as they are.
+	driverErr = sqlite3.Error{Code: sqlite3.ErrError}
+	assert.Equal(t, error(driverErr), (&database{}).Err(driverErr))
+}
+
+func TestSetConstraintDetails(t *testing.T) {
+	dbErr := &db.DatabaseError{Reason: db.ErrUniqueViolation}
+	setConstraintDetails(dbErr, "UNIQUE constraint failed: users.account_id, users.username")
+	assert.Equal(t, "users", dbErr.Table)
+	assert.Equal(t, "account_id", dbErr.Column)
+
+	dbErr = &db.DatabaseError{Reason: db.ErrNotNullViolation}
+	setConstraintDetails(dbErr, "NOT NULL constraint failed: users.username")
+	assert.Equal(t, "users", dbErr.Table)
+	assert.Equal(t, "username", dbErr.Column)
+
+	dbErr = &db.DatabaseError{Reason: db.ErrCheckViolation}
+	setConstraintDetails(dbErr, "CHECK constraint failed: username_length")
+	assert.Equal(t, "username_length", dbErr.Constraint)
+
+	dbErr = &db.DatabaseError{Reason: db.ErrForeignKeyViolation}
+	setConstraintDetails(dbErr, "FOREIGN KEY constraint failed")
+	assert.Equal(t, "", dbErr.Table)
+}
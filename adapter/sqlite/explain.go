@@ -0,0 +1,108 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter"
+)
+
+type queryPlanRow struct {
+	id     int
+	parent int
+	detail string
+}
+
+// ExplainStatement runs EXPLAIN QUERY PLAN on the given query. SQLite can't
+// report run-time statistics, so opts.Analyze is not supported.
+func (*database) ExplainStatement(sess sqladapter.Session, ctx context.Context, query string, args []interface{}, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	if opts.Analyze {
+		return nil, db.ErrNotSupportedByAdapter
+	}
+
+	rows, err := sess.SQL().QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	planRows := []queryPlanRow{}
+	for rows.Next() {
+		var row queryPlanRow
+		var notUsed int
+		if err := rows.Scan(&row.id, &row.parent, &notUsed, &row.detail); err != nil {
+			return nil, err
+		}
+		planRows = append(planRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return newQueryPlan(planRows), nil
+}
+
+// newQueryPlan builds a plan tree from the rows of EXPLAIN QUERY PLAN, each
+// row references its parent by id.
+func newQueryPlan(rows []queryPlanRow) *db.QueryPlan {
+	plan := &db.QueryPlan{}
+
+	raw := make([]string, 0, len(rows))
+	nodes := map[int]*db.QueryPlanNode{}
+
+	for _, row := range rows {
+		raw = append(raw, fmt.Sprintf("%d|%d|%s", row.id, row.parent, row.detail))
+
+		node := &db.QueryPlanNode{
+			Operation: row.detail,
+			Properties: map[string]interface{}{
+				"detail": row.detail,
+			},
+		}
+
+		// Table access looks like "SCAN artist" or "SEARCH TABLE artist USING
+		// INTEGER PRIMARY KEY (rowid=?)".
+		fields := strings.Fields(row.detail)
+		if len(fields) > 1 && (fields[0] == "SCAN" || fields[0] == "SEARCH") {
+			node.Operation = fields[0]
+			table := fields[1]
+			if table == "TABLE" && len(fields) > 2 {
+				table = fields[2]
+			}
+			node.Table = table
+		}
+
+		nodes[row.id] = node
+		if parent, ok := nodes[row.parent]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+	}
+
+	plan.Raw = strings.Join(raw, "\n")
+	return plan
+}
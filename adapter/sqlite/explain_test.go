@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueryPlan(t *testing.T) {
+	plan := newQueryPlan([]queryPlanRow{
+		{id: 2, parent: 0, detail: "SEARCH artist USING INTEGER PRIMARY KEY (rowid=?)"},
+		{id: 5, parent: 0, detail: "CORRELATED SCALAR SUBQUERY 1"},
+		{id: 8, parent: 5, detail: "SCAN TABLE publication"},
+		{id: 20, parent: 0, detail: "USE TEMP B-TREE FOR ORDER BY"},
+	})
+
+	assert.Equal(t, "2|0|SEARCH artist USING INTEGER PRIMARY KEY (rowid=?)\n5|0|CORRELATED SCALAR SUBQUERY 1\n8|5|SCAN TABLE publication\n20|0|USE TEMP B-TREE FOR ORDER BY", plan.Raw)
+
+	assert.Equal(t, 3, len(plan.Nodes))
+
+	assert.Equal(t, "SEARCH", plan.Nodes[0].Operation)
+	assert.Equal(t, "artist", plan.Nodes[0].Table)
+
+	assert.Equal(t, "CORRELATED SCALAR SUBQUERY 1", plan.Nodes[1].Operation)
+	assert.Equal(t, 1, len(plan.Nodes[1].Children))
+	assert.Equal(t, "SCAN", plan.Nodes[1].Children[0].Operation)
+	assert.Equal(t, "publication", plan.Nodes[1].Children[0].Table)
+
+	assert.Equal(t, "USE TEMP B-TREE FOR ORDER BY", plan.Nodes[2].Operation)
+	assert.Equal(t, "", plan.Nodes[2].Table)
+}
@@ -159,7 +159,10 @@ func (h *Helper) SetUp() error {
 			id integer primary key,
 			name varchar,
 			disabled integer,
-			created_at datetime default current_timestamp
+			created_at datetime default current_timestamp,
+			deleted_at datetime,
+			version integer not null default 0,
+			updated_at datetime
 		)`,
 
 		`DROP TABLE IF EXISTS users`,
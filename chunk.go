@@ -0,0 +1,47 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+// ChunkOptions defines how the items of a result set are split into chunks.
+type ChunkOptions struct {
+	// Into is a pointer to a slice each chunk is fetched into, it's reused
+	// from chunk to chunk. Chunks are fetched into a *[]map[string]interface{}
+	// when it's nil.
+	Into interface{}
+
+	// Key is the column chunks are paginated by, its values must be unique and
+	// are compared with the > operator. It defaults to the primary key on
+	// collections with a single primary key. Chunks are fetched with Paginate
+	// when there's no key, in which case the result set should have a stable
+	// order.
+	Key string
+
+	// Transaction makes each chunk be fetched and processed within its own
+	// transaction. The transaction is committed when the chunk function
+	// returns nil and rolled back otherwise.
+	Transaction bool
+}
+
+// ChunkFunc receives a chunk of items, as a pointer to a slice, along with
+// the session the chunk was fetched with: the transaction of the chunk when
+// ChunkOptions.Transaction is set. Returning an error stops the processing.
+type ChunkFunc func(sess Session, batch interface{}) error
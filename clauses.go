@@ -52,6 +52,23 @@ type Selector interface {
 	// The above statement is equivalent to:
 	//
 	//   s.Columns(db.Func("MAX", "id"))
+	//
+	// A struct can be given to select the columns its fields are mapped to,
+	// they're qualified with the table given to From(). The fields of nested
+	// structs tagged with a prefix are selected from the table the prefix
+	// names and aliased with their prefixed names, so JOIN results can be
+	// scanned into them:
+	//
+	//   type Order struct {
+	//     ID       uint64    `db:"id"`
+	//     Customer *Customer `db:"customer,prefix=customer."`
+	//   }
+	//
+	//   s.Columns(Order{}).From("orders").
+	//     LeftJoin("customers AS customer").On("customer.id = orders.customer_id")
+	//
+	// Pointers to nested structs are left nil when all of their columns are
+	// NULL.
 	Columns(columns ...interface{}) Selector
 
 	// From represents a FROM clause and is tipically used after Columns().
@@ -215,6 +232,10 @@ type Selector interface {
 	// database server.
 	Amend(func(queryIn string) (queryOut string)) Selector
 
+	// StrictMapping overrides the strict mapping setting of the session for
+	// this query, see Settings.SetStrictMapping.
+	StrictMapping(bool) Selector
+
 	// Paginate returns a paginator that can display a paginated lists of items.
 	// Paginators ignore previous Offset and Limit settings. Page numbering
 	// starts at 1.
@@ -244,6 +265,22 @@ type Selector interface {
 
 	// Arguments returns the arguments that are prepared for this query.
 	Arguments() []interface{}
+
+	// Explain asks the database to describe the execution plan of the query
+	// using the same arguments the query would be executed with. A nil ctx
+	// means the session's default context.
+	//
+	//   plan, err := s.Explain(ctx, &db.ExplainOptions{Analyze: true})
+	Explain(ctx context.Context, opts *ExplainOptions) (*QueryPlan, error)
+
+	// Chunk runs the query in chunks of at most size rows and passes each
+	// chunk to fn. Chunks are paginated by opts.Key in ascending order, which
+	// replaces any previous order, limit and offset settings, or with
+	// Paginate if no key is given. A nil ctx means the session's default
+	// context. See Result.Chunk.
+	//
+	//   err = s.Chunk(ctx, 500, fn, &db.ChunkOptions{Key: "id", Into: &rows})
+	Chunk(ctx context.Context, size uint, fn ChunkFunc, opts *ChunkOptions) error
 }
 
 // Inserter represents an INSERT statement.
@@ -442,12 +479,16 @@ type Paginator interface {
 
 	// Arguments returns the arguments that are prepared for this query.
 	Arguments() []interface{}
+
+	// Explain asks the database to describe the execution plan of the current
+	// page. See Selector.Explain.
+	Explain(ctx context.Context, opts *ExplainOptions) (*QueryPlan, error)
 }
 
 // ResultMapper defined methods for a result mapper.
 type ResultMapper interface {
 	// All dumps all the results into the given slice, All() expects a pointer to
-	// slice of maps or structs.
+	// slice of maps, structs, scalars or []interface{} tuples.
 	//
 	// The behaviour of One() extends to each one of the results.
 	All(destSlice interface{}) error
@@ -465,6 +506,11 @@ type ResultMapper interface {
 	// If dest if a pointer to struct, each one of the fields will be tested for
 	// a `db` tag which defines the column mapping. The value of the result will
 	// be set as the value of the field.
+	//
+	// If dest is a pointer to a scalar, like an int, a string, a time.Time or a
+	// sql.Scanner, the row must have exactly one column and its value is
+	// scanned into dest. If dest is a pointer to []interface{} the columns are
+	// set by position.
 	One(dest interface{}) error
 }
 
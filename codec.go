@@ -0,0 +1,243 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	codecMap   = map[string]Codec{"json": JSONCodec()}
+	codecMapMu sync.RWMutex
+)
+
+// Codec is the interface implemented by the transformations that can be
+// applied to struct fields with the codec tag option, like:
+//
+//	type Person struct {
+//		SSN     string   `db:"ssn,codec=aes"`
+//		Aliases []string `db:"aliases,codec=json"`
+//	}
+//
+// Values are encoded when they're written and decoded when they're scanned,
+// conditions are not encoded.
+type Codec interface {
+	// Encode transforms the value of a field into the value that is stored in
+	// the database. Nil pointers should be stored as NULL, which is what a nil
+	// value is.
+	Encode(value interface{}) (interface{}, error)
+
+	// Decode transforms a value read from the database and assigns it to dst,
+	// which is a pointer to the field. Decode is called with a nil value for
+	// NULL columns.
+	Decode(value interface{}, dst interface{}) error
+}
+
+// RegisterCodec makes a codec available to the codec tag option under the
+// given name, registering a name twice replaces the previous codec. The
+// "json" codec is registered by default.
+func RegisterCodec(name string, codec Codec) {
+	codecMapMu.Lock()
+	defer codecMapMu.Unlock()
+
+	if name == "" {
+		panic(`Missing codec name`)
+	}
+	if codec == nil {
+		panic(`db.RegisterCodec() called with a nil codec: ` + name)
+	}
+	codecMap[name] = codec
+}
+
+// LookupCodec returns a previously registered codec by name.
+func LookupCodec(name string) (Codec, bool) {
+	codecMapMu.RLock()
+	defer codecMapMu.RUnlock()
+
+	codec, ok := codecMap[name]
+	return codec, ok
+}
+
+// KeyProvider provides the key the AES codec encrypts and decrypts values
+// with.
+type KeyProvider interface {
+	// Key returns a 16, 24 or 32 bytes long key to select AES-128, AES-192 or
+	// AES-256.
+	Key() ([]byte, error)
+}
+
+// KeyProviderFunc is an adapter to allow the use of ordinary functions as key
+// providers.
+type KeyProviderFunc func() ([]byte, error)
+
+// Key calls f().
+func (f KeyProviderFunc) Key() ([]byte, error) {
+	return f()
+}
+
+// StaticKey returns a key provider that always provides the given key.
+func StaticKey(key []byte) KeyProvider {
+	return KeyProviderFunc(func() ([]byte, error) {
+		return key, nil
+	})
+}
+
+type jsonCodec struct{}
+
+// JSONCodec returns a codec that stores values as JSON text, which can be
+// used with any adapter.
+func JSONCodec() Codec {
+	return jsonCodec{}
+}
+
+func (jsonCodec) Encode(value interface{}) (interface{}, error) {
+	if isNilValue(value) {
+		return nil, nil
+	}
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+func (jsonCodec) Decode(value interface{}, dst interface{}) error {
+	if value == nil {
+		return setZero(dst)
+	}
+	buf, err := codecBytes(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, dst)
+}
+
+type aesCodec struct {
+	keys KeyProvider
+}
+
+// AESCodec returns a codec that encrypts values with AES-GCM using the key
+// given by keys. Values are encoded as JSON before being encrypted and stored
+// as base64 text. Since encrypting the same value twice produces different
+// results, encrypted columns can't be used in conditions. The codec has to be
+// registered before it can be used:
+//
+//	db.RegisterCodec("aes", db.AESCodec(db.StaticKey(key)))
+func AESCodec(keys KeyProvider) Codec {
+	return &aesCodec{keys: keys}
+}
+
+func (c *aesCodec) aead() (cipher.AEAD, error) {
+	key, err := c.keys.Key()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (c *aesCodec) Encode(value interface{}) (interface{}, error) {
+	if isNilValue(value) {
+		return nil, nil
+	}
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (c *aesCodec) Decode(value interface{}, dst interface{}) error {
+	if value == nil {
+		return setZero(dst)
+	}
+	encoded, err := codecBytes(value)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return err
+	}
+	aead, err := c.aead()
+	if err != nil {
+		return err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return errors.New(`upper: encrypted value is too short`)
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plaintext, dst)
+}
+
+// codecBytes returns the text held by a value read from the database.
+func codecBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("%w: can't decode %T", ErrUnsupportedValue, value)
+}
+
+// isNilValue reports whether value is a nil interface or a nil pointer, which
+// codecs store as NULL.
+func isNilValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func setZero(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return ErrUnsupportedDestination
+	}
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONCodec(t *testing.T) {
+	codec, ok := LookupCodec("json")
+	assert.True(t, ok)
+
+	stored, err := codec.Encode([]string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, `["a","b"]`, stored)
+
+	var tags []string
+	assert.NoError(t, codec.Decode([]byte(`["c"]`), &tags))
+	assert.Equal(t, []string{"c"}, tags)
+
+	assert.NoError(t, codec.Decode(nil, &tags))
+	assert.Nil(t, tags)
+
+	err = codec.Decode(12, &tags)
+	assert.True(t, errors.Is(err, ErrUnsupportedValue))
+
+	// Nil pointers are stored as NULL.
+	var missing *[]string
+	stored, err = codec.Encode(missing)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestAESCodec(t *testing.T) {
+	codec := AESCodec(StaticKey([]byte("0123456789abcdef")))
+
+	first, err := codec.Encode("123-45-6789")
+	assert.NoError(t, err)
+	second, err := codec.Encode("123-45-6789")
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.NotContains(t, first, "123-45-6789")
+
+	var ssn string
+	assert.NoError(t, codec.Decode(first, &ssn))
+	assert.Equal(t, "123-45-6789", ssn)
+
+	assert.NoError(t, codec.Decode(nil, &ssn))
+	assert.Equal(t, "", ssn)
+
+	// Nil pointers are stored as NULL instead of encrypted.
+	var missing *string
+	stored, err := codec.Encode(missing)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// Values can't be decrypted with a different key.
+	other := AESCodec(StaticKey([]byte("fedcba9876543210")))
+	assert.Error(t, other.Decode(first, &ssn))
+
+	failing := AESCodec(KeyProviderFunc(func() ([]byte, error) {
+		return nil, errors.New("no key")
+	}))
+	_, err = failing.Encode("123-45-6789")
+	assert.EqualError(t, err, "no key")
+}
+
+func TestRegisterCodec(t *testing.T) {
+	_, ok := LookupCodec("test")
+	assert.False(t, ok)
+
+	RegisterCodec("test", JSONCodec())
+	codec, ok := LookupCodec("test")
+	assert.True(t, ok)
+	assert.Equal(t, JSONCodec(), codec)
+
+	assert.Panics(t, func() { RegisterCodec("", JSONCodec()) })
+}
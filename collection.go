@@ -45,6 +45,17 @@ type Collection interface {
 	// newly added element.
 	Insert(interface{}) (InsertResult, error)
 
+	// InsertMany inserts all the items of the given slice, which can hold maps,
+	// structs or pointers to either of them, and returns their IDs in the same
+	// order. Items are written with as few multi-row statements as the adapter
+	// allows, SQL adapters run them within a transaction unless the session is
+	// already one.
+	//
+	// Example:
+	//
+	//   ids, err := sess.Collection("users").InsertMany([]User{{Name: "Ana"}, {Name: "Joe"}})
+	InsertMany(items interface{}) ([]ID, error)
+
 	// InsertReturning is like Insert() but it takes a pointer to map or struct
 	// and, if the operation succeeds, updates it with data from the newly
 	// inserted row. If the database does not support transactions this method
@@ -56,6 +67,13 @@ type Collection interface {
 	// UpdateReturning will fetch the row and update the fields of the passed
 	// item.  If the database does not support transactions this method returns
 	// db.ErrUnsupported
+	//
+	// If the item has a field tagged with the version option, the row is only
+	// updated if its version column still matches the field, the version is
+	// incremented and db.ErrStaleRecord is returned when the row was modified
+	// or deleted by someone else:
+	//
+	//   Version uint64 `db:"version,version"`
 	UpdateReturning(interface{}) error
 
 	// Exists returns true if the collection exists, false otherwise.
@@ -63,4 +81,54 @@ type Collection interface {
 
 	// Truncate removes all elements on the collection.
 	Truncate() error
+
+	// SoftDelete returns a copy of the collection that marks items as deleted
+	// by setting the given column to the current time instead of removing
+	// them. Result sets created by the copy hide deleted items unless
+	// WithDeleted() or OnlyDeleted() are used.
+	//
+	// Example:
+	//
+	//   accounts := sess.Collection("accounts").SoftDelete("deleted_at")
+	//   err = accounts.Find(id).Delete() // UPDATE ... SET deleted_at = ...
+	SoftDelete(column string) Collection
+
+	// SoftDeleteColumn returns the column given to SoftDelete, or an empty
+	// string if the collection does not soft delete items.
+	SoftDeleteColumn() string
+
+	// Scope returns a copy of the collection whose result sets only include
+	// the items that match the given conditions, which are ANDed into Find,
+	// Count, Update and Delete. Conditions on plain values are also set on
+	// the items inserted through the copy. Scopes are merged when Scope is
+	// called more than once, conditions on the same column are replaced. Use
+	// Result.Unscoped to lift the scope of a result set.
+	//
+	// Stores can declare a scope to make sure every query is limited to a
+	// tenant, for instance:
+	//
+	//   func Accounts(sess db.Session) db.Store {
+	//     tenantID := sess.Context().Value(tenantKey{})
+	//     return &AccountsStore{sess.Collection("accounts").Scope(db.Cond{"tenant_id": tenantID})}
+	//   }
+	Scope(cond Cond) Collection
+
+	// ScopeCond returns the conditions given to Scope.
+	ScopeCond() Cond
+}
+
+// SoftDeleter is satisfied by every Collection and by the stores that embed
+// them, only collections returned by SoftDelete report a column.
+//
+// Session.Delete sets the soft delete column of a record instead of removing
+// it when the record's store reports a column, and the store's Find excludes
+// deleted records unless WithDeleted is used:
+//
+//	func Accounts(sess db.Session) db.Store {
+//	  return &AccountsStore{sess.Collection("accounts").SoftDelete("deleted_at")}
+//	}
+type SoftDeleter interface {
+	// SoftDeleteColumn returns the name of the column that holds the time an
+	// item was deleted at.
+	SoftDeleteColumn() string
 }
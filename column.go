@@ -0,0 +1,46 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"github.com/upper/db/v4/internal/adapter"
+)
+
+// ColumnExpr represents a reference to a column.
+type ColumnExpr = adapter.ColumnExpr
+
+// Column references the value of the given column, so it can be used as the
+// value of another column in Result.Update maps or as an argument of Raw and
+// Func expressions.
+//
+// Examples:
+//
+//	// SET "updated_at" = "created_at"
+//	res.Update(map[string]interface{}{"updated_at": db.Column("created_at")})
+//
+//	// SET "total" = "price" * ?
+//	res.Update(map[string]interface{}{
+//		"total": db.Raw("? * ?", db.Column("price"), 3),
+//	})
+func Column(name string) *ColumnExpr {
+	return adapter.NewColumnExpr(name)
+}
@@ -23,6 +23,8 @@ package db
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 // Error messages
@@ -60,4 +62,147 @@ var (
 	ErrTransactionAborted       = errors.New(`upper: transaction was aborted`)
 	ErrNotWithinTransaction     = errors.New(`upper: not within transaction`)
 	ErrNotSupportedByAdapter    = errors.New(`upper: not supported by adapter`)
+	ErrMissingSoftDeleteColumn  = errors.New(`upper: collection has no soft delete column`)
+	ErrStaleRecord              = errors.New(`upper: record was modified or deleted since it was read`)
+	ErrUnknownRelation          = errors.New(`upper: unknown relation`)
+	ErrUnknownCodec             = errors.New(`upper: unknown codec`)
+	ErrInvalidChunkSize         = errors.New(`upper: chunk size must be greater than zero`)
+	ErrInvalidChunkQuery        = errors.New(`upper: chunks can't be offset or, when paginated by a key, ordered`)
+	ErrNoRowsAffected           = errors.New(`upper: no rows were affected`)
+	ErrMixedRecordTypes         = errors.New(`upper: expecting records of the same type`)
 )
+
+// Portable database errors, adapters translate driver errors into these and
+// wrap them into a *DatabaseError.
+var (
+	ErrUniqueViolation     = errors.New(`upper: unique constraint violation`)
+	ErrForeignKeyViolation = errors.New(`upper: foreign key constraint violation`)
+	ErrNotNullViolation    = errors.New(`upper: not null constraint violation`)
+	ErrCheckViolation      = errors.New(`upper: check constraint violation`)
+	ErrDeadlock            = errors.New(`upper: deadlock detected`)
+	ErrLockTimeout         = errors.New(`upper: lock wait timeout`)
+	ErrQueryCanceled       = errors.New(`upper: query canceled`)
+)
+
+// DatabaseError is a driver error that was recognized by the adapter. It can
+// be matched against the portable error it represents and against the
+// original driver error:
+//
+//	if errors.Is(err, db.ErrUniqueViolation) {
+//		var dbErr *db.DatabaseError
+//		if errors.As(err, &dbErr) {
+//			log.Printf("duplicate value for %q", dbErr.Column)
+//		}
+//	}
+type DatabaseError struct {
+	// Reason is one of the portable errors, like ErrUniqueViolation.
+	Reason error
+
+	// Constraint, Table and Column are the names of the constraint, table and
+	// column involved, they're empty if the driver did not report them.
+	Constraint string
+	Table      string
+	Column     string
+
+	// Err is the original driver error.
+	Err error
+}
+
+func (e *DatabaseError) Error() string {
+	details := []string{}
+	if e.Constraint != "" {
+		details = append(details, fmt.Sprintf("constraint %q", e.Constraint))
+	}
+	if e.Table != "" {
+		details = append(details, fmt.Sprintf("table %q", e.Table))
+	}
+	if e.Column != "" {
+		details = append(details, fmt.Sprintf("column %q", e.Column))
+	}
+
+	msg := e.Reason.Error()
+	if len(details) > 0 {
+		msg = fmt.Sprintf("%s (%s)", msg, strings.Join(details, ", "))
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns the portable error and the original driver error.
+func (e *DatabaseError) Unwrap() []error {
+	return []error{e.Reason, e.Err}
+}
+
+// MappingError is returned when strict mapping is enabled and the columns of
+// a result set don't match the fields of the struct they're fetched into, see
+// Settings.SetStrictMapping.
+type MappingError struct {
+	// Type is the struct type the result set was fetched into.
+	Type string
+
+	// UnmappedColumns are the columns that don't map to any field.
+	UnmappedColumns []string
+
+	// MissingFields are the columns of the fields that are not part of the
+	// result set.
+	MissingFields []string
+}
+
+func (e *MappingError) Error() string {
+	details := []string{}
+	if len(e.UnmappedColumns) > 0 {
+		details = append(details, fmt.Sprintf("unmapped columns %s", quoteNames(e.UnmappedColumns)))
+	}
+	if len(e.MissingFields) > 0 {
+		details = append(details, fmt.Sprintf("missing fields %s", quoteNames(e.MissingFields)))
+	}
+	return fmt.Sprintf("upper: can't map result set into %s: %s", e.Type, strings.Join(details, "; "))
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i := range names {
+		quoted[i] = fmt.Sprintf("%q", names[i])
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// RecordError is an error that happened while processing one of the records
+// given to a bulk operation, like Session.SaveAll.
+type RecordError struct {
+	// Index is the position of the record within the given slice.
+	Index int
+
+	Record Record
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Index, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// RecordErrors is returned by bulk operations when one or more records failed
+// validation or hooks, in which case no changes are persisted.
+type RecordErrors []*RecordError
+
+func (e RecordErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%v (and %d more)", e[0], len(e)-1)
+}
+
+// Unwrap returns the errors of every record.
+func (e RecordErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i := range e {
+		errs[i] = e[i]
+	}
+	return errs
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseError(t *testing.T) {
+	driverErr := errors.New(`duplicate key value violates unique constraint "users_username_key"`)
+
+	var err error = &DatabaseError{
+		Reason:     ErrUniqueViolation,
+		Constraint: "users_username_key",
+		Table:      "users",
+		Err:        driverErr,
+	}
+	err = fmt.Errorf("could not save: %w", err)
+
+	assert.True(t, errors.Is(err, ErrUniqueViolation))
+	assert.True(t, errors.Is(err, driverErr))
+	assert.False(t, errors.Is(err, ErrForeignKeyViolation))
+
+	var dbErr *DatabaseError
+	if assert.True(t, errors.As(err, &dbErr)) {
+		assert.Equal(t, "users_username_key", dbErr.Constraint)
+		assert.Equal(t, "users", dbErr.Table)
+		assert.Equal(t, "", dbErr.Column)
+	}
+
+	assert.Equal(t,
+		`could not save: upper: unique constraint violation (constraint "users_username_key", table "users"): duplicate key value violates unique constraint "users_username_key"`,
+		err.Error(),
+	)
+
+	err = &DatabaseError{Reason: ErrDeadlock}
+	assert.True(t, errors.Is(err, ErrDeadlock))
+	assert.Equal(t, "upper: deadlock detected", err.Error())
+}
+
+func TestRecordErrors(t *testing.T) {
+	errEmptyName := errors.New("name is required")
+
+	var err error = RecordErrors{
+		{Index: 1, Err: errEmptyName},
+		{Index: 3, Err: ErrStaleRecord},
+	}
+
+	assert.True(t, errors.Is(err, errEmptyName))
+	assert.True(t, errors.Is(err, ErrStaleRecord))
+	assert.False(t, errors.Is(err, ErrNilRecord))
+
+	var recordErr *RecordError
+	if assert.True(t, errors.As(err, &recordErr)) {
+		assert.Equal(t, 1, recordErr.Index)
+	}
+
+	assert.Equal(t, "record 1: name is required (and 1 more)", err.Error())
+	assert.Equal(t, "record 3: upper: record was modified or deleted since it was read", RecordErrors{{Index: 3, Err: ErrStaleRecord}}.Error())
+}
+
+func TestMustAffect(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	assert.NoError(t, MustAffect(1, nil))
+	assert.NoError(t, MustAffect(3, nil))
+	assert.ErrorIs(t, MustAffect(0, nil), ErrNoRowsAffected)
+	assert.ErrorIs(t, MustAffect(0, errFailed), errFailed)
+}
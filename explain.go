@@ -0,0 +1,55 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+// ExplainOptions defines how a query plan is obtained.
+type ExplainOptions struct {
+	// Analyze executes the query and includes actual run-time statistics in
+	// the plan, if the database supports it.
+	Analyze bool
+}
+
+// QueryPlanNode represents a single operation within a query plan.
+type QueryPlanNode struct {
+	// Operation is the name of the operation, like "Seq Scan" or "SCAN".
+	Operation string
+
+	// Table is the name of the table the operation reads from, if any.
+	Table string
+
+	// Properties holds any other attribute the database reported for this
+	// operation, like costs, estimated rows or timings.
+	Properties map[string]interface{}
+
+	// Children holds the operations this one takes its input from.
+	Children []*QueryPlanNode
+}
+
+// QueryPlan represents the execution plan of a query as described by the
+// database.
+type QueryPlan struct {
+	// Nodes holds the top level operations of the plan.
+	Nodes []*QueryPlanNode
+
+	// Raw is the plan as returned by the database.
+	Raw string
+}
@@ -25,6 +25,7 @@ import (
 	"container/list"
 	"errors"
 	"sync"
+	"time"
 )
 
 const defaultCapacity = 128
@@ -36,11 +37,15 @@ type Cache struct {
 	keys     *list.List
 	items    map[uint64]*list.Element
 	capacity int
+
+	maxIdleTime     time.Duration
+	evictionHandler func(value interface{})
 }
 
 type cacheItem struct {
-	key   uint64
-	value interface{}
+	key      uint64
+	value    interface{}
+	accessed time.Time
 }
 
 // NewCacheWithCapacity initializes a new caching space with the given
@@ -74,6 +79,42 @@ func (c *Cache) init() {
 	c.keys = list.New()
 }
 
+// SetCapacity changes the maximum number of items the cache can hold, older
+// items are evicted if the cache holds more than that.
+func (c *Cache) SetCapacity(capacity int) error {
+	if capacity < 1 {
+		return errors.New("Capacity must be greater than zero.")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = capacity
+	c.evictOverflow()
+	return nil
+}
+
+// SetMaxIdleTime sets the maximum amount of time an item may remain in the
+// cache without being read or written, expired items are evicted. A zero
+// value means items never expire.
+func (c *Cache) SetMaxIdleTime(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxIdleTime = d
+	c.evictExpired()
+}
+
+// SetEvictionHandler sets a function that is called with every value that is
+// evicted from the cache because of its capacity or its maximum idle time.
+// Values removed by Clear are not reported.
+func (c *Cache) SetEvictionHandler(fn func(value interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictionHandler = fn
+}
+
 // Read attempts to retrieve a cached value as a string, if the value does not
 // exists returns an empty string and false.
 func (c *Cache) Read(h Hashable) (string, bool) {
@@ -89,14 +130,35 @@ func (c *Cache) Read(h Hashable) (string, bool) {
 // does not exists returns nil and false.
 func (c *Cache) ReadRaw(h Hashable) (interface{}, bool) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
+	if c.maxIdleTime == 0 {
+		defer c.mu.RUnlock()
+
+		item, ok := c.items[h.Hash()]
+		if ok {
+			return item.Value.(*cacheItem).value, true
+		}
+		return nil, false
+	}
+	c.mu.RUnlock()
+
+	// Items that can expire need to keep track of their last access.
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	item, ok := c.items[h.Hash()]
-	if ok {
-		return item.Value.(*cacheItem).value, true
+	if !ok {
+		return nil, false
+	}
+
+	ci := item.Value.(*cacheItem)
+	if time.Since(ci.accessed) > c.maxIdleTime {
+		c.evict(item)
+		return nil, false
 	}
 
-	return nil, false
+	ci.accessed = time.Now()
+	c.keys.MoveToFront(item)
+	return ci.value, true
 }
 
 // Write stores a value in memory. If the value already exists its overwritten.
@@ -107,20 +169,50 @@ func (c *Cache) Write(h Hashable, value interface{}) {
 	key := h.Hash()
 
 	if item, ok := c.items[key]; ok {
-		item.Value.(*cacheItem).value = value
+		ci := item.Value.(*cacheItem)
+		if evictor, hasOnEvict := ci.value.(HasOnEvict); hasOnEvict && ci.value != value {
+			// The replaced value won't be reachable anymore.
+			evictor.OnEvict()
+		}
+		ci.value = value
+		ci.accessed = time.Now()
 		c.keys.MoveToFront(item)
 		return
 	}
 
-	c.items[key] = c.keys.PushFront(&cacheItem{key, value})
+	c.items[key] = c.keys.PushFront(&cacheItem{key, value, time.Now()})
+
+	c.evictOverflow()
+	c.evictExpired()
+}
 
+func (c *Cache) evictOverflow() {
 	for c.keys.Len() > c.capacity {
-		item := c.keys.Remove(c.keys.Back()).(*cacheItem)
-		delete(c.items, item.key)
+		c.evict(c.keys.Back())
+	}
+}
 
-		if evictor, hasOnEvict := item.value.(HasOnEvict); hasOnEvict {
-			evictor.OnEvict()
+func (c *Cache) evictExpired() {
+	if c.maxIdleTime == 0 {
+		return
+	}
+	for item := c.keys.Back(); item != nil; item = c.keys.Back() {
+		if time.Since(item.Value.(*cacheItem).accessed) <= c.maxIdleTime {
+			return
 		}
+		c.evict(item)
+	}
+}
+
+func (c *Cache) evict(element *list.Element) {
+	item := c.keys.Remove(element).(*cacheItem)
+	delete(c.items, item.key)
+
+	if evictor, hasOnEvict := item.value.(HasOnEvict); hasOnEvict {
+		evictor.OnEvict()
+	}
+	if c.evictionHandler != nil {
+		c.evictionHandler(item.value)
 	}
 }
 
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -69,6 +70,64 @@ func TestCache(t *testing.T) {
 	})
 }
 
+func TestCacheEviction(t *testing.T) {
+	key := func(i int) Hashable {
+		return NewHashable(0, i)
+	}
+
+	evicted := []interface{}{}
+
+	c, err := NewCacheWithCapacity(3)
+	assert.NoError(t, err)
+	c.SetEvictionHandler(func(value interface{}) {
+		evicted = append(evicted, value)
+	})
+
+	t.Run("Capacity", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			c.Write(key(i), i)
+		}
+
+		_, ok := c.ReadRaw(key(0))
+		assert.False(t, ok)
+		assert.Equal(t, []interface{}{0}, evicted)
+	})
+
+	t.Run("SetCapacity", func(t *testing.T) {
+		assert.Error(t, c.SetCapacity(0))
+
+		assert.NoError(t, c.SetCapacity(1))
+		assert.Equal(t, []interface{}{0, 1, 2}, evicted)
+
+		v, ok := c.ReadRaw(key(3))
+		assert.True(t, ok)
+		assert.Equal(t, 3, v)
+	})
+
+	t.Run("MaxIdleTime", func(t *testing.T) {
+		c.SetMaxIdleTime(time.Millisecond * 10)
+
+		v, ok := c.ReadRaw(key(3))
+		assert.True(t, ok)
+		assert.Equal(t, 3, v)
+
+		time.Sleep(time.Millisecond * 20)
+
+		_, ok = c.ReadRaw(key(3))
+		assert.False(t, ok)
+		assert.Equal(t, []interface{}{0, 1, 2, 3}, evicted)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		c.Write(key(4), 4)
+		c.Clear()
+
+		_, ok := c.ReadRaw(key(4))
+		assert.False(t, ok)
+		assert.Equal(t, []interface{}{0, 1, 2, 3}, evicted)
+	})
+}
+
 func BenchmarkNewCache(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		NewCache()
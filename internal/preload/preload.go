@@ -0,0 +1,425 @@
+// Package preload loads the items related to a set of items, as declared by
+// db.HasRelations, using batched IN queries.
+package preload
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// batchSize is the maximum number of keys that are sent within a single IN
+// query.
+const batchSize = 500
+
+const defaultKey = "id"
+
+// Load loads the given relations into dst, which can be a pointer to a struct
+// or a pointer to a slice of structs (or pointers to structs). Nested
+// relations are separated by dots. Columns are mapped to struct fields with
+// the given mapper.
+func Load(sess db.Session, mapper *reflectx.Mapper, dst interface{}, relations []string) error {
+	dstv := reflect.ValueOf(dst)
+	if !dstv.IsValid() || dstv.Kind() != reflect.Ptr || dstv.IsNil() {
+		return db.ErrUnsupportedDestination
+	}
+
+	items, err := collectItems(dstv.Elem())
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	itemType := items[0].Type()
+	declared := relationsOf(sess, itemType)
+
+	names, nested := splitRelations(relations)
+	for _, name := range names {
+		relation, ok := declared[name]
+		if !ok {
+			return fmt.Errorf("%w: %q is not declared by %v", db.ErrUnknownRelation, name, itemType)
+		}
+		field, ok := itemType.FieldByName(name)
+		if !ok {
+			return fmt.Errorf("%w: %v has no field %q", db.ErrUnknownRelation, itemType, name)
+		}
+
+		l := &loader{
+			sess:     sess,
+			mapper:   mapper,
+			relation: withDefaultKeys(relation),
+			field:    field,
+			nested:   nested[name],
+		}
+		if err := l.load(items); err != nil {
+			return fmt.Errorf("preloading %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// collectItems returns the addressable structs held by v.
+func collectItems(v reflect.Value) ([]reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return collectItems(v.Elem())
+	case reflect.Struct:
+		return []reflect.Value{v}, nil
+	case reflect.Slice:
+		items := make([]reflect.Value, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			for item.Kind() == reflect.Ptr && !item.IsNil() {
+				item = item.Elem()
+			}
+			if item.Kind() != reflect.Struct {
+				if item.Kind() == reflect.Ptr {
+					continue
+				}
+				return nil, db.ErrUnsupportedDestination
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, db.ErrUnsupportedDestination
+}
+
+// relationsOf returns the relations declared by the given struct type or by
+// its store.
+func relationsOf(sess db.Session, t reflect.Type) map[string]db.Relation {
+	item := reflect.New(t).Interface()
+	if hasRelations, ok := item.(db.HasRelations); ok {
+		return hasRelations.Relations()
+	}
+	if record, ok := item.(db.Record); ok {
+		if hasRelations, ok := record.Store(sess).(db.HasRelations); ok {
+			return hasRelations.Relations()
+		}
+	}
+	return nil
+}
+
+// splitRelations splits "A.B" into "A" and its nested relation "B", names are
+// returned in the order they were given.
+func splitRelations(relations []string) ([]string, map[string][]string) {
+	names := []string{}
+	nested := map[string][]string{}
+	for _, relation := range relations {
+		name, rest, _ := strings.Cut(relation, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = []string{}
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	return names, nested
+}
+
+func withDefaultKeys(relation db.Relation) db.Relation {
+	if relation.LocalKey == "" {
+		relation.LocalKey = defaultKey
+	}
+	if relation.RelatedKey == "" {
+		relation.RelatedKey = defaultKey
+	}
+	return relation
+}
+
+type loader struct {
+	sess     db.Session
+	mapper   *reflectx.Mapper
+	relation db.Relation
+	field    reflect.StructField
+	nested   []string
+}
+
+func (l *loader) load(items []reflect.Value) error {
+	fieldType := l.field.Type
+
+	switch l.relation.Kind {
+	case db.HasOne, db.BelongsTo:
+		if reflectx.Deref(fieldType).Kind() != reflect.Struct {
+			return fmt.Errorf("field %q must be a struct or a pointer to struct", l.field.Name)
+		}
+	case db.HasMany, db.ManyToMany:
+		if fieldType.Kind() != reflect.Slice || reflectx.Deref(fieldType.Elem()).Kind() != reflect.Struct {
+			return fmt.Errorf("field %q must be a slice of structs", l.field.Name)
+		}
+	default:
+		return fmt.Errorf("unknown relation kind %d", l.relation.Kind)
+	}
+
+	switch l.relation.Kind {
+	case db.HasOne:
+		return l.loadOne(items, l.relation.LocalKey, l.relation.ForeignKey)
+	case db.BelongsTo:
+		return l.loadOne(items, l.relation.ForeignKey, l.relation.RelatedKey)
+	case db.HasMany:
+		return l.loadMany(items)
+	}
+	return l.loadManyToMany(items)
+}
+
+// loadOne assigns to each item the related item whose relatedColumn matches
+// the item's localColumn.
+func (l *loader) loadOne(items []reflect.Value, localColumn, relatedColumn string) error {
+	keys, err := l.columnValues(items, localColumn)
+	if err != nil {
+		return err
+	}
+
+	related, index, err := l.fetchRelated(reflectx.Deref(l.field.Type), relatedColumn, keys)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		value, ok, err := l.columnValue(item, localColumn)
+		if err != nil {
+			return err
+		}
+		field := item.FieldByIndex(l.field.Index)
+		field.Set(reflect.Zero(field.Type()))
+		if !ok {
+			continue
+		}
+		if positions := index[KeyOf(value)]; len(positions) > 0 {
+			assign(field, related.Index(positions[0]))
+		}
+	}
+
+	return nil
+}
+
+// loadMany assigns to each item the related items whose foreign key matches
+// the item's local key.
+func (l *loader) loadMany(items []reflect.Value) error {
+	keys, err := l.columnValues(items, l.relation.LocalKey)
+	if err != nil {
+		return err
+	}
+
+	related, index, err := l.fetchRelated(reflectx.Deref(l.field.Type.Elem()), l.relation.ForeignKey, keys)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		value, ok, err := l.columnValue(item, l.relation.LocalKey)
+		if err != nil {
+			return err
+		}
+		field := item.FieldByIndex(l.field.Index)
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		if !ok {
+			continue
+		}
+		for _, i := range index[KeyOf(value)] {
+			field.Set(reflect.Append(field, elem(field.Type().Elem(), related.Index(i))))
+		}
+	}
+
+	return nil
+}
+
+// loadManyToMany looks up the join collection first and then assigns to each
+// item the related items that are joined to it.
+func (l *loader) loadManyToMany(items []reflect.Value) error {
+	keys, err := l.columnValues(items, l.relation.LocalKey)
+	if err != nil {
+		return err
+	}
+
+	joined := map[string][]string{}
+	relatedKeys := []interface{}{}
+	seen := map[string]struct{}{}
+
+	for start := 0; start < len(keys); start += batchSize {
+		var rows []map[string]interface{}
+		err := l.sess.Collection(l.relation.JoinCollection).
+			Find(db.Cond{l.relation.JoinForeignKey: db.In(keys[start:min(start+batchSize, len(keys))]...)}).
+			All(&rows)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			localKey, relatedKey := row[l.relation.JoinForeignKey], row[l.relation.JoinRelatedKey]
+			if localKey == nil || relatedKey == nil {
+				continue
+			}
+			joined[KeyOf(localKey)] = append(joined[KeyOf(localKey)], KeyOf(relatedKey))
+			if _, ok := seen[KeyOf(relatedKey)]; !ok {
+				seen[KeyOf(relatedKey)] = struct{}{}
+				relatedKeys = append(relatedKeys, relatedKey)
+			}
+		}
+	}
+
+	related, index, err := l.fetchRelated(reflectx.Deref(l.field.Type.Elem()), l.relation.RelatedKey, relatedKeys)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		value, ok, err := l.columnValue(item, l.relation.LocalKey)
+		if err != nil {
+			return err
+		}
+		field := item.FieldByIndex(l.field.Index)
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		if !ok {
+			continue
+		}
+		for _, relatedKey := range joined[KeyOf(value)] {
+			if positions := index[relatedKey]; len(positions) > 0 {
+				field.Set(reflect.Append(field, elem(field.Type().Elem(), related.Index(positions[0]))))
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchRelated fetches the items of the related collection whose column
+// matches any of the given keys, and indexes their positions by that column.
+func (l *loader) fetchRelated(relatedType reflect.Type, column string, keys []interface{}) (reflect.Value, map[string][]int, error) {
+	related := reflect.New(reflect.SliceOf(relatedType))
+
+	for start := 0; start < len(keys); start += batchSize {
+		batch := reflect.New(related.Elem().Type())
+		err := l.relatedFinder(relatedType).
+			Find(db.Cond{column: db.In(keys[start:min(start+batchSize, len(keys))]...)}).
+			All(batch.Interface())
+		if err != nil {
+			return reflect.Value{}, nil, err
+		}
+		related.Elem().Set(reflect.AppendSlice(related.Elem(), batch.Elem()))
+	}
+
+	if len(l.nested) > 0 {
+		if err := Load(l.sess, l.mapper, related.Interface(), l.nested); err != nil {
+			return reflect.Value{}, nil, err
+		}
+	}
+
+	index := map[string][]int{}
+	for i := 0; i < related.Elem().Len(); i++ {
+		value, ok, err := l.columnValue(related.Elem().Index(i), column)
+		if err != nil {
+			return reflect.Value{}, nil, err
+		}
+		if ok {
+			index[KeyOf(value)] = append(index[KeyOf(value)], i)
+		}
+	}
+
+	return related.Elem(), index, nil
+}
+
+// relatedFinder returns the store of the related items if they're records of
+// the related collection, so its soft delete and scope conditions apply, or
+// the related collection otherwise.
+func (l *loader) relatedFinder(relatedType reflect.Type) interface {
+	Find(...interface{}) db.Result
+} {
+	if record, ok := reflect.New(relatedType).Interface().(db.Record); ok {
+		if store := record.Store(l.sess); store != nil && store.Name() == l.relation.Collection {
+			return store
+		}
+	}
+	return l.sess.Collection(l.relation.Collection)
+}
+
+// columnValues returns the distinct non-zero values the given column has
+// within items.
+func (l *loader) columnValues(items []reflect.Value, column string) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(items))
+	seen := map[string]struct{}{}
+	for _, item := range items {
+		value, ok, err := l.columnValue(item, column)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if _, ok := seen[KeyOf(value)]; ok {
+			continue
+		}
+		seen[KeyOf(value)] = struct{}{}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// columnValue returns the value of the field mapped to column, false is
+// returned if the value is nil or zero.
+func (l *loader) columnValue(item reflect.Value, column string) (interface{}, bool, error) {
+	fi, ok := l.mapper.TypeMap(item.Type()).Names[column]
+	if !ok {
+		return nil, false, fmt.Errorf("%v has no field mapped to column %q", item.Type(), column)
+	}
+
+	v := item
+	for _, i := range fi.Index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, false, nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.IsZero() {
+		return nil, false, nil
+	}
+	return v.Interface(), true, nil
+}
+
+// KeyOf returns a representation of a key value that does not depend on the
+// type the value was scanned into.
+func KeyOf(value interface{}) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.IsValid() {
+		value = v.Interface()
+	}
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// assign sets field to the related struct, or to a pointer to it.
+func assign(field reflect.Value, related reflect.Value) {
+	field.Set(elem(field.Type(), related))
+}
+
+// elem returns the related struct, or a pointer to it, as a value of type t.
+func elem(t reflect.Type, related reflect.Value) reflect.Value {
+	if t.Kind() == reflect.Ptr {
+		return related.Addr()
+	}
+	return related
+}
@@ -0,0 +1,71 @@
+package preload
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+type account struct {
+	ID    int64  `db:"id"`
+	Users []user `db:"-"`
+}
+
+type user struct {
+	ID        int64    `db:"id"`
+	AccountID *int64   `db:"account_id"`
+	Account   *account `db:"-"`
+}
+
+func TestSplitRelations(t *testing.T) {
+	names, nested := splitRelations([]string{"Items.Product", "Customer", "Items.Tags", "Items"})
+	assert.Equal(t, []string{"Items", "Customer"}, names)
+	assert.Equal(t, []string{"Product", "Tags"}, nested["Items"])
+	assert.Equal(t, []string{}, nested["Customer"])
+}
+
+func TestCollectItems(t *testing.T) {
+	users := []*user{{ID: 1}, nil, {ID: 2}}
+	items, err := collectItems(reflect.ValueOf(&users).Elem())
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	one := user{ID: 1}
+	items, err = collectItems(reflect.ValueOf(&one).Elem())
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.True(t, items[0].CanSet())
+
+	maps := []map[string]interface{}{{"id": 1}}
+	_, err = collectItems(reflect.ValueOf(&maps).Elem())
+	assert.ErrorIs(t, err, db.ErrUnsupportedDestination)
+}
+
+func TestColumnValue(t *testing.T) {
+	l := &loader{mapper: reflectx.NewMapper("db")}
+
+	accountID := int64(3)
+	value, ok, err := l.columnValue(reflect.ValueOf(user{AccountID: &accountID}), "account_id")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), value)
+
+	_, ok, err = l.columnValue(reflect.ValueOf(user{}), "account_id")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = l.columnValue(reflect.ValueOf(user{}), "missing")
+	assert.Error(t, err)
+}
+
+func TestKeyOf(t *testing.T) {
+	assert.Equal(t, KeyOf(int64(12)), KeyOf(uint32(12)))
+	assert.Equal(t, KeyOf(int64(12)), KeyOf([]byte("12")))
+	assert.NotEqual(t, KeyOf(1), KeyOf(2))
+
+	id := int64(12)
+	assert.Equal(t, KeyOf(int64(12)), KeyOf(&id))
+}
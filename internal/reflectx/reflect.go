@@ -6,7 +6,8 @@
 package reflectx
 
 import (
-	"fmt"
+	"database/sql"
+	"database/sql/driver"
 	"reflect"
 	"runtime"
 	"strings"
@@ -32,6 +33,23 @@ type StructMap struct {
 	Index []*FieldInfo
 	Paths map[string]*FieldInfo
 	Names map[string]*FieldInfo
+
+	// folded maps the lower case names to fields, it's nil unless the mapper
+	// folds case.
+	folded map[string]*FieldInfo
+}
+
+// Lookup returns the field mapped to the given name. Names are matched
+// regardless of case if the mapper folds case and there's no exact match.
+func (f StructMap) Lookup(name string) (*FieldInfo, bool) {
+	if fi, ok := f.Names[name]; ok {
+		return fi, true
+	}
+	if f.folded != nil {
+		fi, ok := f.folded[strings.ToLower(name)]
+		return fi, ok
+	}
+	return nil, false
 }
 
 // GetByPath returns a *FieldInfo for a given string path.
@@ -64,6 +82,7 @@ type Mapper struct {
 	tagName    string
 	tagMapFunc func(string) string
 	mapFunc    func(string) string
+	foldCase   bool
 	mutex      sync.Mutex
 }
 
@@ -88,6 +107,13 @@ func NewMapperTagFunc(tagName string, mapFunc, tagMapFunc func(string) string) *
 	}
 }
 
+// FoldCase makes the mappings of m match names regardless of case, see
+// StructMap.Lookup. It must be called before m is used.
+func (m *Mapper) FoldCase() *Mapper {
+	m.foldCase = true
+	return m
+}
+
 // NewMapperFunc returns a new mapper which optionally obeys a field tag and
 // a struct field name mapper func given by f.  Tags will take precedence, but
 // for any other field, the mapped name will be f(field.Name)
@@ -106,6 +132,12 @@ func (m *Mapper) TypeMap(t reflect.Type) *StructMap {
 	mapping, ok := m.cache[t]
 	if !ok {
 		mapping = getMapping(t, m.tagName, m.mapFunc, m.tagMapFunc)
+		if m.foldCase {
+			mapping.folded = make(map[string]*FieldInfo, len(mapping.Names))
+			for name, fi := range mapping.Names {
+				mapping.folded[strings.ToLower(name)] = fi
+			}
+		}
 		m.cache[t] = mapping
 	}
 	m.mutex.Unlock()
@@ -279,7 +311,19 @@ func methodName() string {
 type typeQueue struct {
 	t  reflect.Type
 	fi *FieldInfo
-	pp string // Parent path
+	pp string // Prefix of the paths of the fields, including the separator
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// isValueType reports whether t is a struct that is stored as a single value,
+// like sql.NullString, so its fields are not mapped.
+func isValueType(t reflect.Type) bool {
+	t = Deref(t)
+	return reflect.PointerTo(t).Implements(scannerType) || t.Implements(valuerType)
 }
 
 // A copying append that creates a new slice each time.
@@ -332,7 +376,7 @@ func getMapping(t reflect.Type, tagName string, mapFunc, tagMapFunc func(string)
 			if len(parts) > 1 {
 				name = parts[0]
 				for _, opt := range parts[1:] {
-					kv := strings.Split(opt, "=")
+					kv := strings.SplitN(opt, "=", 2)
 					if len(kv) > 1 {
 						fi.Options[kv[0]] = kv[1]
 					} else {
@@ -341,16 +385,30 @@ func getMapping(t reflect.Type, tagName string, mapFunc, tagMapFunc func(string)
 				}
 			}
 
-			if tagMapFunc != nil {
-				tag = tagMapFunc(tag)
+			if tag != "" {
+				// tags that only hold options, like ",omitempty", name the field
+				// the same way untagged fields are named
+				if name == "" && mapFunc != nil && !f.Anonymous {
+					name = mapFunc(f.Name)
+				} else if name != "" && name != "-" && tagMapFunc != nil {
+					name = tagMapFunc(name)
+				}
 			}
 
 			fi.Name = name
 
-			if tq.pp == "" || (tq.pp == "" && tag == "") {
-				fi.Path = fi.Name
-			} else {
-				fi.Path = fmt.Sprintf("%s.%s", tq.pp, fi.Name)
+			fi.Path = tq.pp + fi.Name
+
+			// the fields of nested structs are prefixed with the path of the
+			// struct, unless a different prefix is given with the prefix option.
+			// Structs without a name, like untagged fields when there's no name
+			// function, don't prefix their fields.
+			pp := fi.Path
+			if pp != "" {
+				pp += "."
+			}
+			if prefix, ok := fi.Options["prefix"]; ok {
+				pp = tq.pp + prefix
 			}
 
 			// if the name is "-", disabled via a tag, skip it
@@ -365,9 +423,8 @@ func getMapping(t reflect.Type, tagName string, mapFunc, tagMapFunc func(string)
 
 			// bfs search of anonymous embedded structs
 			if f.Anonymous {
-				pp := tq.pp
-				if tag != "" {
-					pp = fi.Path
+				if _, ok := fi.Options["prefix"]; !ok && (tag == "" || name == "") {
+					pp = tq.pp
 				}
 
 				fi.Embedded = true
@@ -379,10 +436,10 @@ func getMapping(t reflect.Type, tagName string, mapFunc, tagMapFunc func(string)
 				}
 				fi.Children = make([]*FieldInfo, nChildren)
 				queue = append(queue, typeQueue{Deref(f.Type), &fi, pp})
-			} else if fi.Zero.Kind() == reflect.Struct || (fi.Zero.Kind() == reflect.Ptr && fi.Zero.Type().Elem().Kind() == reflect.Struct) {
+			} else if (fi.Zero.Kind() == reflect.Struct || (fi.Zero.Kind() == reflect.Ptr && fi.Zero.Type().Elem().Kind() == reflect.Struct)) && !isValueType(f.Type) {
 				fi.Index = apnd(tq.fi.Index, fieldPos)
 				fi.Children = make([]*FieldInfo, Deref(f.Type).NumField())
-				queue = append(queue, typeQueue{Deref(f.Type), &fi, fi.Path})
+				queue = append(queue, typeQueue{Deref(f.Type), &fi, pp})
 			}
 
 			fi.Index = apnd(tq.fi.Index, fieldPos)
@@ -1,6 +1,7 @@
 package reflectx
 
 import (
+	"database/sql"
 	"reflect"
 	"strings"
 	"testing"
@@ -351,6 +352,86 @@ func TestReflectMapper(t *testing.T) {
 		assert.Nil(t, m.GetByPath("STRATEGYID"))      // not mapped by tag
 	})
 
+	t.Run("PrefixedFields", func(t *testing.T) {
+		type Customer struct {
+			ID   int    `db:"id"`
+			Name string `db:"name"`
+		}
+
+		type Order struct {
+			ID       int       `db:"id"`
+			Customer *Customer `db:"customer,prefix=c_"`
+			Shipping Customer  `db:"shipping"`
+			Billing  struct {
+				Customer `db:"customer,prefix=customer."`
+			} `db:"billing"`
+		}
+
+		m := NewMapper("db")
+		mapping := m.TypeMap(reflect.TypeOf(Order{}))
+
+		assert.NotNil(t, mapping.GetByPath("id"))
+		assert.NotNil(t, mapping.GetByPath("c_id"))
+		assert.NotNil(t, mapping.GetByPath("c_name"))
+		assert.Nil(t, mapping.GetByPath("customer.id"))
+		assert.NotNil(t, mapping.GetByPath("shipping.id"))
+		assert.NotNil(t, mapping.GetByPath("billing.customer.name"))
+		assert.Equal(t, "c_", mapping.GetByPath("customer").Options["prefix"])
+	})
+
+	t.Run("UntaggedNestedStruct", func(t *testing.T) {
+		type Address struct {
+			Street string `db:"street"`
+		}
+
+		type Person struct {
+			Name string `db:"name"`
+			Addr Address
+		}
+
+		m := NewMapper("db")
+		mapping := m.TypeMap(reflect.TypeOf(Person{}))
+
+		fi, ok := mapping.Lookup("street")
+		assert.True(t, ok)
+		assert.Equal(t, "street", fi.Path)
+		assert.Nil(t, mapping.GetByPath(".street"))
+
+		p := Person{Addr: Address{Street: "Main"}}
+		assert.Equal(t, "Main", m.FieldByName(reflect.ValueOf(p), "street").Interface())
+	})
+
+	t.Run("TagMapFuncAndFoldCase", func(t *testing.T) {
+		type Account struct {
+			ID        int            `db:"ID"`
+			FirstName string         `db:",omitempty"`
+			Nickname  sql.NullString `db:"nickname"`
+			LastLogin string
+			Skipped   string `db:"-"`
+		}
+
+		m := NewMapperTagFunc("db", strings.ToLower, strings.ToUpper).FoldCase()
+		mapping := m.TypeMap(reflect.TypeOf(Account{}))
+
+		assert.NotNil(t, mapping.GetByPath("ID"))
+		assert.NotNil(t, mapping.GetByPath("NICKNAME"))
+		assert.NotNil(t, mapping.GetByPath("lastlogin"))
+		assert.Nil(t, mapping.GetByPath("-"))
+		assert.Nil(t, mapping.GetByPath("NICKNAME.String"))
+
+		fi := mapping.GetByPath("firstname")
+		if assert.NotNil(t, fi) {
+			assert.Contains(t, fi.Options, "omitempty")
+		}
+
+		fi, ok := mapping.Lookup("id")
+		assert.True(t, ok)
+		assert.Equal(t, "ID", fi.Name)
+
+		_, ok = NewMapper("db").TypeMap(reflect.TypeOf(Account{})).Lookup("id")
+		assert.False(t, ok)
+	})
+
 	t.Run("MapperFuncWithTags", func(t *testing.T) {
 		type Person struct {
 			ID           int
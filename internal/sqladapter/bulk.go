@@ -0,0 +1,710 @@
+package sqladapter
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/preload"
+	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// bulkBatchSize is the maximum number of records that are written or matched
+// by a single statement of a bulk operation.
+const bulkBatchSize = 500
+
+// recordSlice returns the records held by the given slice (or pointer to
+// slice), elements can be records or structs whose pointers are records. All
+// the records must be of the same type, since they're written through the
+// store of the first one.
+func recordSlice(records interface{}) ([]db.Record, error) {
+	v := reflect.ValueOf(records)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, db.ErrNilRecord
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("upper: expecting a slice of records but got %T", records)
+	}
+
+	list := make([]db.Record, v.Len())
+	for i := range list {
+		item := v.Index(i)
+		if item.Kind() == reflect.Struct {
+			item = item.Addr()
+		}
+		if (item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface) && item.IsNil() {
+			return nil, &db.RecordError{Index: i, Err: db.ErrNilRecord}
+		}
+		record, ok := item.Interface().(db.Record)
+		if !ok {
+			return nil, fmt.Errorf("upper: expecting a slice of records but got %T", records)
+		}
+		if reflect.TypeOf(record).Kind() != reflect.Ptr {
+			return nil, &db.RecordError{Index: i, Record: record, Err: db.ErrExpectingPointerToStruct}
+		}
+		if i > 0 && reflect.TypeOf(record) != reflect.TypeOf(list[0]) {
+			return nil, &db.RecordError{Index: i, Record: record, Err: db.ErrMixedRecordTypes}
+		}
+		list[i] = record
+	}
+
+	return list, nil
+}
+
+// itemSlice returns the items held by the given slice (or pointer to slice).
+func itemSlice(items interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(items)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("upper: expecting a slice of items but got %T", items)
+	}
+
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+	return list, nil
+}
+
+// bulk runs fn within a transaction, unless the session is already a
+// transaction.
+func (sess *sessionWithContext) bulk(records interface{}, fn func(tx db.Session, records []db.Record) error) error {
+	list, err := recordSlice(records)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	if sess.IsTransaction() {
+		return fn(sess, list)
+	}
+	return sess.TxContext(sess.Context(), func(tx db.Session) error {
+		return fn(tx, list)
+	}, nil)
+}
+
+func (sess *sessionWithContext) CreateAll(records interface{}) error {
+	return sess.bulk(records, func(tx db.Session, records []db.Record) error {
+		return recordWriteAll(tx, records[0].Store(tx), records, make([]bool, len(records)))
+	})
+}
+
+func (sess *sessionWithContext) SaveAll(records interface{}) error {
+	return sess.bulk(records, recordSaveAll)
+}
+
+func (sess *sessionWithContext) DeleteAll(records interface{}) error {
+	return sess.bulk(records, recordDeleteAll)
+}
+
+func recordSaveAll(sess db.Session, records []db.Record) error {
+	store := records[0].Store(sess)
+
+	var errs db.RecordErrors
+	for i, record := range records {
+		if err := recordBeforeSave(sess, record); err != nil {
+			errs = append(errs, &db.RecordError{Index: i, Record: record, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if saver, ok := store.(db.StoreSaver); ok {
+		for i, record := range records {
+			if err := saver.Save(record); err != nil {
+				return db.RecordErrors{{Index: i, Record: record, Err: err}}
+			}
+		}
+	} else {
+		update, err := recordsExist(store, records)
+		if err != nil {
+			return err
+		}
+		if err := recordWriteAll(sess, store, records, update); err != nil {
+			return err
+		}
+	}
+
+	for i, record := range records {
+		if err := recordAfterSave(sess, record); err != nil {
+			errs = append(errs, &db.RecordError{Index: i, Record: record, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// recordsExist reports which of the given records already exist, only records
+// with all their primary keys set are looked up. Versioned records with all
+// their primary keys set are reported as existing, see recordSave.
+func recordsExist(store db.Store, records []db.Record) ([]bool, error) {
+	exist := make([]bool, len(records))
+
+	ids := make([]db.Cond, len(records))
+	for i, record := range records {
+		keys, values, err := recordPrimaryKeyFieldValues(store, record)
+		if err != nil {
+			return nil, err
+		}
+		id := db.Cond{}
+		for j := range values {
+			if values[j] != reflect.Zero(reflect.TypeOf(values[j])).Interface() {
+				id[keys[j]] = values[j]
+			}
+		}
+		if len(id) > 0 && len(id) == len(values) {
+			if isVersioned(store.Session(), record) {
+				exist[i] = true
+				continue
+			}
+			ids[i] = id
+		}
+	}
+
+	keys, err := store.Session().(Session).PrimaryKeys(store.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) != 1 {
+		for i := range ids {
+			if ids[i] == nil {
+				continue
+			}
+			count, err := store.Find(ids[i]).WithDeleted().Count()
+			if err != nil {
+				return nil, err
+			}
+			exist[i] = count > 0
+		}
+		return exist, nil
+	}
+
+	values := []interface{}{}
+	for i := range ids {
+		if ids[i] != nil {
+			values = append(values, ids[i][keys[0]])
+		}
+	}
+
+	found := map[string]struct{}{}
+	for start := 0; start < len(values); start += bulkBatchSize {
+		var rows []map[string]interface{}
+		err := store.Find(db.Cond{keys[0]: db.In(values[start:min(start+bulkBatchSize, len(values))]...)}).
+			WithDeleted().
+			Select(keys[0]).
+			All(&rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			found[preload.KeyOf(row[keys[0]])] = struct{}{}
+		}
+	}
+
+	for i := range ids {
+		if ids[i] != nil {
+			_, exist[i] = found[preload.KeyOf(ids[i][keys[0]])]
+		}
+	}
+
+	return exist, nil
+}
+
+// recordWriteAll creates the given records, or updates them if update is true
+// for the record. Validation and Before hooks run for every record before
+// anything is written.
+func recordWriteAll(sess db.Session, store db.Store, records []db.Record, update []bool) error {
+	now := sessionNow(sess)
+
+	var errs db.RecordErrors
+	for i, record := range records {
+		var err error
+		if update[i] {
+			err = recordBeforeUpdate(sess, record)
+		} else {
+			setAutoTimestamps(sqlbuilder.MapperOf(sess), record, now, true)
+			err = recordBeforeCreate(sess, record)
+		}
+		if err != nil {
+			errs = append(errs, &db.RecordError{Index: i, Record: record, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	creates := make([]db.Record, 0, len(records))
+	for i, record := range records {
+		if update[i] {
+			continue
+		}
+		if creator, ok := store.(db.StoreCreator); ok {
+			if err := creator.Create(record); err != nil {
+				return db.RecordErrors{{Index: i, Record: record, Err: err}}
+			}
+			continue
+		}
+		creates = append(creates, record)
+	}
+	if err := insertAllReturning(sess, store, creates); err != nil {
+		return err
+	}
+
+	col, canBatch := batchUpdateCollection(sess, store)
+
+	done := make([]bool, len(records))
+	batched, batchedItems, batchedColumns := []int{}, []interface{}{}, [][]string{}
+	for i, record := range records {
+		if !update[i] {
+			done[i] = true
+			trackRecord(sess, record)
+			continue
+		}
+
+		columns, changed := recordUpdateColumns(sess, store, record)
+		if !changed {
+			// Nothing to write, the hooks run anyway.
+			done[i] = true
+			continue
+		}
+		setAutoTimestamps(sqlbuilder.MapperOf(sess), record, now, false)
+		if canBatch && !isVersioned(sess, record) {
+			batched = append(batched, i)
+			batchedItems = append(batchedItems, record)
+			batchedColumns = append(batchedColumns, columns)
+			continue
+		}
+		if err := recordStoreUpdate(sess, store, record, columns); err != nil {
+			return db.RecordErrors{{Index: i, Record: record, Err: err}}
+		}
+		done[i] = true
+		trackRecord(sess, record)
+	}
+
+	if len(batched) > 0 {
+		if err := col.updateAll(batchedItems, batchedColumns); err != nil {
+			return err
+		}
+		for _, i := range batched {
+			done[i] = true
+			trackRecord(sess, records[i])
+		}
+	}
+
+	for i, record := range records {
+		if !done[i] {
+			continue
+		}
+
+		var err error
+		if update[i] {
+			err = recordAfterUpdate(sess, record)
+		} else {
+			err = recordAfterCreate(sess, record)
+		}
+		if err != nil {
+			errs = append(errs, &db.RecordError{Index: i, Record: record, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// batchUpdateCollection returns the collection the records of the store can be
+// updated in batches through, false is returned if the store has its own
+// update method or the collection has no single primary key.
+func batchUpdateCollection(sess db.Session, store db.Store) (*collectionWithSession, bool) {
+	if _, ok := store.(db.StoreUpdater); ok {
+		return nil, false
+	}
+	col, ok := storeCollection(sess, store).(*collectionWithSession)
+	if !ok {
+		return nil, false
+	}
+	pks, err := col.PrimaryKeys()
+	if err != nil || len(pks) != 1 {
+		return nil, false
+	}
+	return col, true
+}
+
+// insertAllReturning inserts the given records and refreshes them with the
+// actual data from the database, like InsertReturning does.
+func insertAllReturning(sess db.Session, store db.Store, records []db.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	col, ok := storeCollection(sess, store).(*collectionWithSession)
+	if !ok {
+		for _, record := range records {
+			if err := storeCollection(sess, store).InsertReturning(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	items := make([]interface{}, len(records))
+	for i := range records {
+		items[i] = records[i]
+	}
+
+	ids, err := col.insertAll(items)
+	if err != nil {
+		return err
+	}
+	return col.refreshAll(items, ids)
+}
+
+func recordDeleteAll(sess db.Session, records []db.Record) error {
+	store := records[0].Store(sess)
+	deleter, hasDeleter := store.(db.StoreDeleter)
+
+	var errs db.RecordErrors
+	conds := make([]db.Cond, len(records))
+	for i, record := range records {
+		err := recordBeforeDelete(sess, record)
+		if err == nil && !hasDeleter {
+			conds[i], err = recordID(store, record)
+		}
+		if err != nil {
+			errs = append(errs, &db.RecordError{Index: i, Record: record, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if hasDeleter {
+		for i, record := range records {
+			if err := deleter.Delete(record); err != nil {
+				return db.RecordErrors{{Index: i, Record: record, Err: err}}
+			}
+		}
+	} else {
+		column := recordSoftDeleteColumn(store)
+		for start := 0; start < len(records); start += bulkBatchSize {
+			end := min(start+bulkBatchSize, len(records))
+			cond := batchConds(conds[start:end])
+
+			var err error
+			if column != "" {
+				err = recordSoftDelete(store, records[start:end], column, cond)
+			} else {
+				err = store.Find(cond).Delete()
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, record := range records {
+		if err := recordAfterDelete(sess, record); err != nil {
+			errs = append(errs, &db.RecordError{Index: i, Record: record, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// batchConds merges the conditions that identify many records into one.
+// Records identified by the same single column are matched with IN.
+func batchConds(conds []db.Cond) interface{} {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+
+	column := ""
+	values := make([]interface{}, 0, len(conds))
+	for _, cond := range conds {
+		if len(cond) != 1 {
+			return orConds(conds)
+		}
+		for key, value := range cond {
+			name := fmt.Sprintf("%v", key)
+			if column == "" {
+				column = name
+			}
+			if _, isComparison := value.(*db.Comparison); isComparison || name != column {
+				return orConds(conds)
+			}
+			values = append(values, value)
+		}
+	}
+
+	return db.Cond{column: db.In(values...)}
+}
+
+func orConds(conds []db.Cond) *db.OrExpr {
+	exprs := make([]db.LogicalExpr, len(conds))
+	for i := range conds {
+		exprs[i] = conds[i]
+	}
+	return db.Or(exprs...)
+}
+
+// insertAll inserts the given items and returns their IDs in the same order.
+// Consecutive items with the same columns and no explicit primary key are
+// inserted by a single statement if the adapter supports it, as long as the
+// statement does not exceed the placeholder limit of the adapter.
+func (c *collectionWithSession) insertAll(items []interface{}) ([]interface{}, error) {
+	pks, err := c.PrimaryKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	mapper := c.session.FieldMapper()
+
+	if len(c.scope) > 0 {
+		scoped := make([]interface{}, len(items))
+		for i := range items {
+			if scoped[i], err = withScopeValues(mapper, items[i], c.scope); err != nil {
+				return nil, err
+			}
+		}
+		items = scoped
+	}
+
+	batcher, canBatch := c.adapter.(batchInserter)
+	canBatch = canBatch && len(pks) == 1
+
+	maxPlaceholders := 0
+	if limiter, ok := c.adapter.(placeholderLimiter); ok {
+		maxPlaceholders = limiter.MaxPlaceholders()
+	}
+
+	ids := make([]interface{}, len(items))
+	for i := 0; i < len(items); {
+		columns, values, err := sqlbuilder.Map(items[i], &sqlbuilder.MapOptions{Mapper: mapper})
+		if err != nil {
+			return nil, err
+		}
+
+		if !canBatch || slices.Contains(columns, pks[0]) {
+			res, err := c.Insert(items[i])
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = res.ID()
+			i++
+			continue
+		}
+
+		batchSize := bulkBatchSize
+		if maxPlaceholders > 0 && len(values) > 0 {
+			batchSize = max(1, min(batchSize, maxPlaceholders/len(values)))
+		}
+
+		rows := [][]interface{}{values}
+		j := i + 1
+		for ; j < len(items) && j-i < batchSize; j++ {
+			nextColumns, nextValues, err := sqlbuilder.Map(items[j], &sqlbuilder.MapOptions{Mapper: mapper})
+			if err != nil {
+				return nil, err
+			}
+			if !slices.Equal(columns, nextColumns) {
+				break
+			}
+			rows = append(rows, nextValues)
+		}
+
+		batchIDs, err := batcher.InsertBatch(c, columns, rows)
+		if err != nil {
+			return nil, err
+		}
+		if len(batchIDs) != len(rows) {
+			return nil, fmt.Errorf("upper: expecting %d IDs after inserting into %q, got %d", len(rows), c.Name(), len(batchIDs))
+		}
+		copy(ids[i:j], batchIDs)
+		i = j
+	}
+
+	return ids, nil
+}
+
+// updateAll updates the given items, which are matched by their primary key,
+// and refreshes them with the actual data from the database, like
+// UpdateReturning does. Only the given columns of each item are written, or
+// all of them if its columns are nil. Each batch of items is updated by a
+// single statement that picks the value of every column with a CASE
+// expression on the primary key, columns an item does not write keep their
+// current value.
+func (c *collectionWithSession) updateAll(items []interface{}, columns [][]string) error {
+	pks, err := c.PrimaryKeys()
+	if err != nil {
+		return err
+	}
+	if len(pks) != 1 {
+		return fmt.Errorf(db.ErrMissingPrimaryKeys.Error(), c.Name())
+	}
+
+	mapper := c.session.FieldMapper()
+
+	ids := make([]interface{}, len(items))
+	values := make([]map[string]interface{}, len(items))
+	for i := range items {
+		ids[i] = mapper.FieldByName(reflect.ValueOf(items[i]), pks[0]).Interface()
+
+		itemColumns := columns[i]
+		if itemColumns == nil {
+			// Same columns UpdateReturning writes.
+			if itemColumns, _, err = sqlbuilder.Map(items[i], &sqlbuilder.MapOptions{Mapper: mapper}); err != nil {
+				return err
+			}
+		}
+		if values[i], err = sqlbuilder.MapColumns(items[i], itemColumns, &sqlbuilder.MapOptions{Mapper: mapper}); err != nil {
+			return err
+		}
+		delete(values[i], pks[0])
+	}
+
+	maxPlaceholders := 0
+	if limiter, ok := c.adapter.(placeholderLimiter); ok {
+		maxPlaceholders = limiter.MaxPlaceholders()
+	}
+
+	for start := 0; start < len(items); {
+		// Every value takes two placeholders, its key and itself, and every item
+		// takes one more within the IN condition.
+		end, placeholders := start, 0
+		for end < len(items) && end-start < bulkBatchSize {
+			n := 2*len(values[end]) + 1
+			if maxPlaceholders > 0 && end > start && placeholders+n > maxPlaceholders {
+				break
+			}
+			placeholders += n
+			end++
+		}
+
+		if err := c.updateBatch(pks[0], ids[start:end], values[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+
+	return c.refreshAll(items, ids)
+}
+
+// updateBatch writes the values of the rows identified by ids with a single
+// UPDATE statement.
+func (c *collectionWithSession) updateBatch(pk string, ids []interface{}, values []map[string]interface{}) error {
+	columns := []string{}
+	for i := range values {
+		for column := range values[i] {
+			if !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	slices.Sort(columns)
+
+	set := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		expr := "CASE ?"
+		args := []interface{}{db.Column(pk)}
+		for i := range values {
+			if value, ok := values[i][column]; ok {
+				expr += " WHEN ? THEN ?"
+				args = append(args, ids[i], value)
+			}
+		}
+		expr += " ELSE ? END"
+		args = append(args, db.Column(column))
+		set[column] = db.Raw(expr, args...)
+	}
+
+	conds := db.Cond{}
+	for k, v := range c.scope {
+		conds[k] = v
+	}
+	conds[pk] = db.In(ids...)
+
+	_, err := c.session.SQL().Update(c.Name()).Set(set).Where(conds).Exec()
+	return err
+}
+
+// refreshAll fetches the rows identified by ids and copies their values into
+// the given items.
+func (c *collectionWithSession) refreshAll(items []interface{}, ids []interface{}) error {
+	pks, err := c.PrimaryKeys()
+	if err != nil {
+		return err
+	}
+	if len(pks) == 0 {
+		return fmt.Errorf(db.ErrMissingPrimaryKeys.Error(), c.Name())
+	}
+
+	mapper := c.session.FieldMapper()
+
+	if len(pks) > 1 {
+		for i := range items {
+			row := reflect.New(reflect.TypeOf(items[i]).Elem())
+			if err := refreshing(c.Find(ids[i])).One(row.Interface()); err != nil {
+				return err
+			}
+			copyValidFields(mapper, items[i], row)
+		}
+		return nil
+	}
+
+	itemType := reflect.TypeOf(items[0]).Elem()
+	for start := 0; start < len(ids); start += bulkBatchSize {
+		end := min(start+bulkBatchSize, len(ids))
+
+		rows := reflect.New(reflect.SliceOf(itemType))
+		err := refreshing(c.Find(db.Cond{pks[0]: db.In(ids[start:end]...)}).WithDeleted()).All(rows.Interface())
+		if err != nil {
+			return err
+		}
+
+		fetched := map[string]reflect.Value{}
+		for k := 0; k < rows.Elem().Len(); k++ {
+			row := rows.Elem().Index(k).Addr()
+			fetched[preload.KeyOf(mapper.FieldByName(row, pks[0]).Interface())] = row
+		}
+
+		for i := start; i < end; i++ {
+			row, ok := fetched[preload.KeyOf(ids[i])]
+			if !ok {
+				return fmt.Errorf("upper: could not find item %v after writing it into %q", ids[i], c.Name())
+			}
+			copyValidFields(mapper, items[i], row)
+		}
+	}
+
+	return nil
+}
+
+// copyValidFields copies the valid fields of the src struct into dst.
+func copyValidFields(mapper *reflectx.Mapper, dst interface{}, src reflect.Value) {
+	dstV := reflect.ValueOf(dst)
+	fields := mapper.ValidFieldMap(src)
+	for name := range fields {
+		mapper.FieldByName(dstV, name).Set(fields[name])
+	}
+}
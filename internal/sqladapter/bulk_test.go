@@ -0,0 +1,84 @@
+package sqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+)
+
+type bulkItem struct {
+	ID uint64 `db:"id"`
+}
+
+func (*bulkItem) Store(sess db.Session) db.Store {
+	return nil
+}
+
+type otherBulkItem struct {
+	ID uint64 `db:"id"`
+}
+
+func (*otherBulkItem) Store(sess db.Session) db.Store {
+	return nil
+}
+
+func TestRecordSlice(t *testing.T) {
+	items := []bulkItem{{ID: 1}, {ID: 2}}
+
+	records, err := recordSlice(items)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Same(t, &items[1], records[1])
+	}
+
+	records, err = recordSlice(&[]*bulkItem{{ID: 1}})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	_, err = recordSlice([]*bulkItem{{ID: 1}, nil})
+	assert.ErrorIs(t, err, db.ErrNilRecord)
+
+	_, err = recordSlice(bulkItem{})
+	assert.Error(t, err)
+
+	_, err = recordSlice([]int{1})
+	assert.Error(t, err)
+
+	_, err = recordSlice([]db.Record{&bulkItem{ID: 1}, &otherBulkItem{ID: 2}})
+	assert.ErrorIs(t, err, db.ErrMixedRecordTypes)
+}
+
+func TestItemSlice(t *testing.T) {
+	items, err := itemSlice([]bulkItem{{ID: 1}, {ID: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{bulkItem{ID: 1}, bulkItem{ID: 2}}, items)
+
+	items, err = itemSlice(&[]map[string]interface{}{{"id": 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": 1}}, items)
+
+	items, err = itemSlice([]bulkItem{})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = itemSlice(bulkItem{})
+	assert.Error(t, err)
+}
+
+func TestBatchConds(t *testing.T) {
+	assert.Equal(t,
+		db.Cond{"id": 1},
+		batchConds([]db.Cond{{"id": 1}}),
+	)
+
+	assert.Equal(t,
+		db.Cond{"id": db.In(1, 2, 3)},
+		batchConds([]db.Cond{{"id": 1}, {"id": 2}, {"id": 3}}),
+	)
+
+	or, ok := batchConds([]db.Cond{{"a": 1, "b": 2}, {"a": 3, "b": 4}}).(*db.OrExpr)
+	if assert.True(t, ok) {
+		assert.Len(t, or.Expressions(), 2)
+	}
+}
@@ -3,8 +3,10 @@ package sqladapter
 import (
 	"fmt"
 	"reflect"
+	"slices"
 
 	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
 	"github.com/upper/db/v4/internal/sqlbuilder"
 )
@@ -55,12 +57,39 @@ type Collection interface {
 
 	// SQLBuilder returns a db.SQL instance.
 	SQL() db.SQL
+
+	// SoftDelete returns a copy of the collection that soft deletes items by
+	// setting the given column.
+	SoftDelete(column string) db.Collection
+
+	// SoftDeleteColumn returns the column given to SoftDelete.
+	SoftDeleteColumn() string
+
+	// Scope returns a copy of the collection that is limited to the items
+	// matching the given conditions.
+	Scope(cond db.Cond) db.Collection
+
+	// ScopeCond returns the conditions given to Scope.
+	ScopeCond() db.Cond
 }
 
 type finder interface {
 	Find(Collection, *Result, ...interface{}) db.Result
 }
 
+// batchInserter is implemented by collection adapters that are able to insert
+// many rows with a single statement. The IDs of the new rows must be returned
+// in the same order the rows were given.
+type batchInserter interface {
+	InsertBatch(col Collection, columns []string, rows [][]interface{}) ([]interface{}, error)
+}
+
+// placeholderLimiter is implemented by collection adapters whose driver does
+// not accept statements with more than MaxPlaceholders arguments.
+type placeholderLimiter interface {
+	MaxPlaceholders() int
+}
+
 type condsFilter interface {
 	FilterConds(...interface{}) []interface{}
 }
@@ -75,6 +104,9 @@ type collectionWithSession struct {
 	*collection
 
 	session Session
+
+	softDeleteColumn string
+	scope            db.Cond
 }
 
 func newCollection(name string, adapter CollectionAdapter) *collection {
@@ -99,11 +131,42 @@ func (c *collectionWithSession) Name() string {
 	return c.name
 }
 
+func (c *collectionWithSession) SoftDelete(column string) db.Collection {
+	copied := *c
+	copied.softDeleteColumn = column
+	return &copied
+}
+
+func (c *collectionWithSession) SoftDeleteColumn() string {
+	return c.softDeleteColumn
+}
+
+func (c *collectionWithSession) Scope(cond db.Cond) db.Collection {
+	copied := *c
+	copied.scope = make(db.Cond, len(c.scope)+len(cond))
+	for k, v := range c.scope {
+		copied.scope[k] = v
+	}
+	for k, v := range cond {
+		copied.scope[k] = v
+	}
+	return &copied
+}
+
+func (c *collectionWithSession) ScopeCond() db.Cond {
+	return c.scope
+}
+
 func (c *collectionWithSession) Count() (uint64, error) {
 	return c.Find().Count()
 }
 
 func (c *collectionWithSession) Insert(item interface{}) (db.InsertResult, error) {
+	item, err := withScopeValues(c.session.FieldMapper(), item, c.scope)
+	if err != nil {
+		return nil, err
+	}
+
 	id, err := c.adapter.Insert(c, item)
 	if err != nil {
 		return nil, err
@@ -112,6 +175,39 @@ func (c *collectionWithSession) Insert(item interface{}) (db.InsertResult, error
 	return db.NewInsertResult(id), nil
 }
 
+func (c *collectionWithSession) InsertMany(items interface{}) ([]db.ID, error) {
+	list, err := itemSlice(items)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	var ids []interface{}
+	if c.session.IsTransaction() {
+		ids, err = c.insertAll(list)
+	} else {
+		err = c.session.TxContext(c.session.Context(), func(tx db.Session) error {
+			copied := *c
+			copied.session = tx.(Session)
+
+			var err error
+			ids, err = copied.insertAll(list)
+			return err
+		}, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]db.ID, len(ids))
+	for i := range ids {
+		res[i] = ids[i]
+	}
+	return res, nil
+}
+
 func (c *collectionWithSession) PrimaryKeys() ([]string, error) {
 	return c.session.PrimaryKeys(c.Name())
 }
@@ -145,6 +241,13 @@ func (c *collectionWithSession) Find(conds ...interface{}) db.Result {
 		c.Name(),
 		filteredConds,
 	)
+	res = res.withSession(c.session)
+	if c.softDeleteColumn != "" {
+		res = res.softDelete(c.softDeleteColumn)
+	}
+	if len(c.scope) > 0 {
+		res = res.withScope(c.scope)
+	}
 	if f, ok := c.adapter.(finder); ok {
 		return f.Find(c, res, conds...)
 	}
@@ -199,7 +302,12 @@ func (c *collectionWithSession) InsertReturning(item interface{}) error {
 
 	// Insert item as is and grab the returning ID.
 	var newItemRes db.Result
-	id, err := col.Insert(item)
+	var id db.InsertResult
+	scopedItem, err := withScopeValues(c.session.FieldMapper(), item, c.scope)
+	if err != nil {
+		goto cancel
+	}
+	id, err = col.Insert(scopedItem)
 	if err != nil {
 		goto cancel
 	}
@@ -217,7 +325,7 @@ func (c *collectionWithSession) InsertReturning(item interface{}) error {
 	}
 
 	// Fetch the row that was just interted into newItem
-	err = newItemRes.One(newItem)
+	err = refreshing(newItemRes).One(newItem)
 	if err != nil {
 		goto cancel
 	}
@@ -225,9 +333,9 @@ func (c *collectionWithSession) InsertReturning(item interface{}) error {
 	switch reflect.ValueOf(newItem).Elem().Kind() {
 	case reflect.Struct:
 		// Get valid fields from newItem to overwrite those that are on item.
-		newItemFieldMap = sqlbuilder.Mapper.ValidFieldMap(reflect.ValueOf(newItem))
+		newItemFieldMap = c.session.FieldMapper().ValidFieldMap(reflect.ValueOf(newItem))
 		for fieldName := range newItemFieldMap {
-			sqlbuilder.Mapper.FieldByName(itemValue, fieldName).Set(newItemFieldMap[fieldName])
+			c.session.FieldMapper().FieldByName(itemValue, fieldName).Set(newItemFieldMap[fieldName])
 		}
 	case reflect.Map:
 		newItemV := reflect.ValueOf(newItem).Elem()
@@ -264,6 +372,14 @@ cancel:
 }
 
 func (c *collectionWithSession) UpdateReturning(item interface{}) error {
+	return c.updateReturning(item, nil)
+}
+
+// updateReturning works like UpdateReturning but only the given columns are
+// updated, unless columns is nil.
+func (c *collectionWithSession) updateReturning(item interface{}, columns []string) error {
+	var values interface{}
+
 	if item == nil || reflect.TypeOf(item).Kind() != reflect.Ptr {
 		return fmt.Errorf("Expecting a pointer but got %T", item)
 	}
@@ -303,27 +419,43 @@ func (c *collectionWithSession) UpdateReturning(item interface{}) error {
 	itemValue := reflect.ValueOf(item)
 
 	conds := db.Cond{}
+	for k, v := range c.scope {
+		conds[k] = v
+	}
 	for _, pk := range pks {
-		conds[pk] = db.Eq(sqlbuilder.Mapper.FieldByName(itemValue, pk).Interface())
+		conds[pk] = db.Eq(c.session.FieldMapper().FieldByName(itemValue, pk).Interface())
+	}
+
+	versionColumn, version, hasVersion := itemVersion(c.session.FieldMapper(), itemValue)
+
+	// The version the item was read with, it's given back to the item if the
+	// update is not committed.
+	var expectedVersion reflect.Value
+	if hasVersion {
+		expectedVersion = reflect.ValueOf(version.Interface())
 	}
 
 	col := tx.Collection(c.Name())
 
-	err = col.Find(conds).Update(item)
+	if hasVersion {
+		err = updateVersioned(tx, c.Name(), conds, item, columns, versionColumn, version)
+	} else if values, err = updateValues(c.session.FieldMapper(), item, columns); err == nil && !isEmptyMap(values) {
+		err = col.Find(conds).Update(values)
+	}
 	if err != nil {
 		goto cancel
 	}
 
-	if err = col.Find(conds).One(defaultItem); err != nil {
+	if err = refreshing(col.Find(conds)).One(defaultItem); err != nil {
 		goto cancel
 	}
 
 	switch reflect.ValueOf(defaultItem).Elem().Kind() {
 	case reflect.Struct:
 		// Get valid fields from defaultItem to overwrite those that are on item.
-		defaultItemFieldMap = sqlbuilder.Mapper.ValidFieldMap(reflect.ValueOf(defaultItem))
+		defaultItemFieldMap = c.session.FieldMapper().ValidFieldMap(reflect.ValueOf(defaultItem))
 		for fieldName := range defaultItemFieldMap {
-			sqlbuilder.Mapper.FieldByName(itemValue, fieldName).Set(defaultItemFieldMap[fieldName])
+			c.session.FieldMapper().FieldByName(itemValue, fieldName).Set(defaultItemFieldMap[fieldName])
 		}
 	case reflect.Map:
 		defaultItemV := reflect.ValueOf(defaultItem).Elem()
@@ -341,14 +473,20 @@ func (c *collectionWithSession) UpdateReturning(item interface{}) error {
 	if !isTransaction {
 		// This is only executed if t.Session() was **not** a transaction and if
 		// sess was created with sess.NewTransaction().
-		return tx.Commit()
+		if err = tx.Commit(); err != nil {
+			goto cancel
+		}
 	}
-	return err
+	return nil
 
 cancel:
 	// This goto label should only be used when we got an error within a
 	// transaction and we don't want to continue.
 
+	if hasVersion {
+		version.Set(expectedVersion)
+	}
+
 	if !isTransaction {
 		// This is only executed if t.Session() was **not** a transaction and if
 		// sess was created with sess.NewTransaction().
@@ -357,6 +495,80 @@ cancel:
 	return err
 }
 
+// itemVersion returns the column and the field of a struct item that is tagged
+// with the version option.
+func itemVersion(mapper *reflectx.Mapper, itemValue reflect.Value) (string, reflect.Value, bool) {
+	itemValue = reflect.Indirect(itemValue)
+	if itemValue.Kind() != reflect.Struct {
+		return "", reflect.Value{}, false
+	}
+	for _, fi := range mapper.TypeMap(itemValue.Type()).Index {
+		if _, ok := fi.Options["version"]; ok {
+			return fi.Name, reflectx.FieldByIndexes(itemValue, fi.Index), true
+		}
+	}
+	return "", reflect.Value{}, false
+}
+
+// updateVersioned updates the item only if its version column still holds the
+// version the item was read with, the version is incremented on success. If
+// no rows are affected db.ErrStaleRecord is returned and the item is left
+// untouched.
+func updateVersioned(sess Session, table string, conds db.Cond, item interface{}, columns []string, column string, version reflect.Value) error {
+	expected := reflect.ValueOf(version.Interface())
+
+	switch version.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		version.SetInt(version.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		version.SetUint(version.Uint() + 1)
+	default:
+		return fmt.Errorf("upper: version column %q must be an integer, got %v", column, version.Type())
+	}
+
+	if columns != nil {
+		columns = append(slices.Clip(columns), column)
+	}
+	values, err := updateValues(sess.FieldMapper(), item, columns)
+	if err != nil {
+		version.Set(expected)
+		return err
+	}
+
+	res, err := sess.SQL().
+		Update(table).
+		Set(values).
+		Where(conds, db.Cond{column: expected.Interface()}).
+		Exec()
+	if err == nil {
+		var affected int64
+		if affected, err = res.RowsAffected(); err == nil && affected == 0 {
+			err = db.ErrStaleRecord
+		}
+	}
+	if err != nil {
+		version.Set(expected)
+		return err
+	}
+
+	return nil
+}
+
+// updateValues returns the values of the given columns of item, or item itself
+// if columns is nil. Zero values of the given columns are kept, even if their
+// fields are tagged with omitempty, so columns changed to zero are written.
+func updateValues(mapper *reflectx.Mapper, item interface{}, columns []string) (interface{}, error) {
+	if columns == nil {
+		return item, nil
+	}
+	return sqlbuilder.MapColumns(item, columns, &sqlbuilder.MapOptions{Mapper: mapper})
+}
+
+func isEmptyMap(values interface{}) bool {
+	set, ok := values.(map[string]interface{})
+	return ok && len(set) == 0
+}
+
 func (c *collectionWithSession) Truncate() error {
 	stmt := exql.Statement{
 		Type:  exql.Truncate,
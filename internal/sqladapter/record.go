@@ -35,6 +35,35 @@ func recordID(store db.Store, record db.Record) (db.Cond, error) {
 	return id, nil
 }
 
+// recordSoftDeleteColumn returns the name of the column the records of the
+// store are soft deleted with, or an empty string if they're removed on
+// delete.
+func recordSoftDeleteColumn(store db.Store) string {
+	if deleter, ok := store.(db.SoftDeleter); ok {
+		return deleter.SoftDeleteColumn()
+	}
+	return ""
+}
+
+// recordSoftDelete sets the soft delete column of the records matching conds,
+// both on the database and on the given records.
+func recordSoftDelete(store db.Store, records []db.Record, column string, conds interface{}) error {
+	deletedAt := sessionNow(store.Session())
+
+	err := store.Find(conds).
+		And(db.Cond{column: db.IsNull()}).
+		WithDeleted().
+		Update(map[string]interface{}{column: deletedAt})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		setColumnTime(sqlbuilder.MapperOf(store.Session()), record, column, deletedAt)
+	}
+	return nil
+}
+
 func recordPrimaryKeyFieldValues(store db.Store, record db.Record) ([]string, []interface{}, error) {
 	sess := store.Session()
 
@@ -43,7 +72,7 @@ func recordPrimaryKeyFieldValues(store db.Store, record db.Record) ([]string, []
 		return nil, nil, err
 	}
 
-	fields := sqlbuilder.Mapper.FieldsByName(reflect.ValueOf(record), pKeys)
+	fields := sqlbuilder.MapperOf(sess).FieldsByName(reflect.ValueOf(record), pKeys)
 
 	values := make([]interface{}, 0, len(fields))
 	for i := range fields {
@@ -55,21 +84,56 @@ func recordPrimaryKeyFieldValues(store db.Store, record db.Record) ([]string, []
 	return pKeys, values, nil
 }
 
-func recordCreate(store db.Store, record db.Record) error {
-	sess := store.Session()
+func recordSave(store db.Store, record db.Record) error {
+	if saver, ok := store.(db.StoreSaver); ok {
+		return saver.Save(record)
+	}
 
-	if validator, ok := record.(db.Validator); ok {
-		if err := validator.Validate(); err != nil {
-			return err
+	id := db.Cond{}
+	keys, values, err := recordPrimaryKeyFieldValues(store, record)
+	if err != nil {
+		return err
+	}
+	for i := range values {
+		if values[i] != reflect.Zero(reflect.TypeOf(values[i])).Interface() {
+			id[keys[i]] = values[i]
 		}
 	}
 
-	if hook, ok := record.(db.BeforeCreateHook); ok {
-		if err := hook.BeforeCreate(sess); err != nil {
-			return err
+	if len(id) > 0 && len(id) == len(values) {
+		// Versioned records are always updated, a versioned record that was
+		// deleted by someone else is stale instead of new.
+		if isVersioned(store.Session(), record) {
+			return recordUpdate(store, record)
+		}
+
+		// check if record exists before updating it, soft deleted records
+		// are updated as well
+		exists, _ := store.Find(id).WithDeleted().Count()
+		if exists > 0 {
+			return recordUpdate(store, record)
 		}
 	}
 
+	return recordCreate(store, record)
+}
+
+// isVersioned reports whether the record has a field tagged with the version
+// option.
+func isVersioned(sess db.Session, record db.Record) bool {
+	_, _, ok := itemVersion(sqlbuilder.MapperOf(sess), reflect.ValueOf(record))
+	return ok
+}
+
+func recordCreate(store db.Store, record db.Record) error {
+	sess := store.Session()
+
+	setAutoTimestamps(sqlbuilder.MapperOf(sess), record, sessionNow(sess), true)
+
+	if err := recordBeforeCreate(sess, record); err != nil {
+		return err
+	}
+
 	if creator, ok := store.(db.StoreCreator); ok {
 		if err := creator.Create(record); err != nil {
 			return err
@@ -79,43 +143,193 @@ func recordCreate(store db.Store, record db.Record) error {
 			return err
 		}
 	}
+	trackRecord(sess, record)
 
-	if hook, ok := record.(db.AfterCreateHook); ok {
-		if err := hook.AfterCreate(sess); err != nil {
-			return err
-		}
-	}
-	return nil
+	return recordAfterCreate(sess, record)
 }
 
 func recordUpdate(store db.Store, record db.Record) error {
 	sess := store.Session()
 
-	if validator, ok := record.(db.Validator); ok {
-		if err := validator.Validate(); err != nil {
-			return err
-		}
+	if err := recordBeforeUpdate(sess, record); err != nil {
+		return err
 	}
 
-	if hook, ok := record.(db.BeforeUpdateHook); ok {
-		if err := hook.BeforeUpdate(sess); err != nil {
-			return err
-		}
+	columns, changed := recordUpdateColumns(sess, store, record)
+	if !changed {
+		// Nothing to write.
+		return recordAfterUpdate(sess, record)
+	}
+
+	setAutoTimestamps(sqlbuilder.MapperOf(sess), record, sessionNow(sess), false)
+
+	if err := recordStoreUpdate(sess, store, record, columns); err != nil {
+		return err
 	}
+	trackRecord(sess, record)
+
+	return recordAfterUpdate(sess, record)
+}
 
+// recordStoreUpdate updates the given columns of the record, or all of them if
+// columns is nil.
+func recordStoreUpdate(sess db.Session, store db.Store, record db.Record, columns []string) error {
 	if updater, ok := store.(db.StoreUpdater); ok {
-		if err := updater.Update(record); err != nil {
-			return err
-		}
-	} else {
-		if err := record.Store(sess).UpdateReturning(record); err != nil {
-			return err
+		return updater.Update(record)
+	}
+	if columns != nil {
+		if col, ok := storeCollection(sess, store).(*collectionWithSession); ok {
+			return col.updateReturning(record, columns)
 		}
 	}
+	return record.Store(sess).UpdateReturning(record)
+}
+
+// storeCollection returns the collection of the store bound to sess, keeping
+// the conditions the store was scoped with.
+func storeCollection(sess db.Session, store db.Store) db.Collection {
+	col := sess.Collection(store.Name())
+	if scope := store.ScopeCond(); len(scope) > 0 {
+		return col.Scope(scope)
+	}
+	return col
+}
+
+func recordValidate(sess db.Session, record db.Record) error {
+	if err := db.ValidateSession(sess, record); err != nil {
+		return err
+	}
+	if validator, ok := record.(db.Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+func recordBeforeCreate(sess db.Session, record db.Record) error {
+	if err := recordValidate(sess, record); err != nil {
+		return err
+	}
+	if hook, ok := record.(db.BeforeCreateContextHook); ok {
+		return hook.BeforeCreateContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.BeforeCreateHook); ok {
+		return hook.BeforeCreate(sess)
+	}
+	return nil
+}
+
+func recordAfterCreate(sess db.Session, record db.Record) error {
+	if hook, ok := record.(db.AfterCreateContextHook); ok {
+		return hook.AfterCreateContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.AfterCreateHook); ok {
+		return hook.AfterCreate(sess)
+	}
+	return nil
+}
 
+func recordBeforeUpdate(sess db.Session, record db.Record) error {
+	if err := recordValidate(sess, record); err != nil {
+		return err
+	}
+	if hook, ok := record.(db.BeforeUpdateContextHook); ok {
+		return hook.BeforeUpdateContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.BeforeUpdateHook); ok {
+		return hook.BeforeUpdate(sess)
+	}
+	return nil
+}
+
+func recordAfterUpdate(sess db.Session, record db.Record) error {
+	if hook, ok := record.(db.AfterUpdateContextHook); ok {
+		return hook.AfterUpdateContext(sess.Context(), sess)
+	}
 	if hook, ok := record.(db.AfterUpdateHook); ok {
-		if err := hook.AfterUpdate(sess); err != nil {
-			return err
+		return hook.AfterUpdate(sess)
+	}
+	return nil
+}
+
+func recordBeforeDelete(sess db.Session, record db.Record) error {
+	if hook, ok := record.(db.BeforeDeleteContextHook); ok {
+		return hook.BeforeDeleteContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.BeforeDeleteHook); ok {
+		return hook.BeforeDelete(sess)
+	}
+	return nil
+}
+
+func recordAfterDelete(sess db.Session, record db.Record) error {
+	if hook, ok := record.(db.AfterDeleteContextHook); ok {
+		return hook.AfterDeleteContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.AfterDeleteHook); ok {
+		return hook.AfterDelete(sess)
+	}
+	return nil
+}
+
+func recordBeforeSave(sess db.Session, record db.Record) error {
+	if hook, ok := record.(db.BeforeSaveContextHook); ok {
+		return hook.BeforeSaveContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.BeforeSaveHook); ok {
+		return hook.BeforeSave(sess)
+	}
+	return nil
+}
+
+func recordAfterSave(sess db.Session, record db.Record) error {
+	if hook, ok := record.(db.AfterSaveContextHook); ok {
+		return hook.AfterSaveContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.AfterSaveHook); ok {
+		return hook.AfterSave(sess)
+	}
+	return nil
+}
+
+func recordAfterFind(sess db.Session, record db.Record) error {
+	if hook, ok := record.(db.AfterFindContextHook); ok {
+		return hook.AfterFindContext(sess.Context(), sess)
+	}
+	if hook, ok := record.(db.AfterFindHook); ok {
+		return hook.AfterFind(sess)
+	}
+	return nil
+}
+
+// eachRecord calls fn with every record held by dst, which can be a pointer to
+// a record or a pointer to a slice of records. Values that are not records
+// are skipped.
+func eachRecord(dst interface{}, fn func(db.Record) error) error {
+	dstV := reflect.ValueOf(dst)
+	if dstV.Kind() != reflect.Ptr || dstV.IsNil() {
+		return nil
+	}
+
+	if record, ok := dst.(db.Record); ok {
+		return fn(record)
+	}
+
+	items := dstV.Elem()
+	if items.Kind() != reflect.Slice {
+		return nil
+	}
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+		if item.Kind() == reflect.Struct {
+			item = item.Addr()
+		}
+		if item.Kind() != reflect.Ptr || item.IsNil() {
+			continue
+		}
+		if record, ok := item.Interface().(db.Record); ok {
+			if err := fn(record); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -22,12 +22,18 @@
 package sqladapter
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/immutable"
+	"github.com/upper/db/v4/internal/preload"
+	"github.com/upper/db/v4/internal/sqlbuilder"
 )
 
 type Result struct {
@@ -55,10 +61,71 @@ type result struct {
 	nextPageCursorValue interface{}
 	prevPageCursorValue interface{}
 
-	fields  []interface{}
-	orderBy []interface{}
-	groupBy []interface{}
-	conds   [][]interface{}
+	fields   []interface{}
+	distinct bool
+	orderBy  []interface{}
+	groupBy  []interface{}
+	conds    [][]interface{}
+
+	softDeleteColumn string
+	softDeleteScope  softDeleteScope
+
+	scope    db.Cond
+	unscoped bool
+
+	preload []string
+
+	strictMapping *bool
+
+	// refresh is true for result sets that read items back after writing
+	// them.
+	refresh bool
+
+	sess Session
+}
+
+// now returns the current time according to the clock of the session the
+// result set belongs to.
+func (res *result) now() time.Time {
+	if res.sess != nil {
+		return res.sess.Now()
+	}
+	return time.Now()
+}
+
+// softDeleteScope determines which items of a soft delete collection are
+// part of a result set.
+type softDeleteScope uint8
+
+const (
+	softDeleteScopeExcludeDeleted softDeleteScope = iota
+	softDeleteScopeWithDeleted
+	softDeleteScopeOnlyDeleted
+)
+
+// implicitConds returns the conditions that are added to the result set by
+// the collection that created it, its scope and its soft delete scope.
+func (res *result) implicitConds() []interface{} {
+	conds := res.softDeleteConds()
+	if len(res.scope) > 0 && !res.unscoped {
+		conds = append(conds, res.scope)
+	}
+	return conds
+}
+
+// softDeleteConds returns the conditions that limit the result set to the
+// items within its soft delete scope.
+func (res *result) softDeleteConds() []interface{} {
+	if res.softDeleteColumn == "" {
+		return nil
+	}
+	switch res.softDeleteScope {
+	case softDeleteScopeExcludeDeleted:
+		return []interface{}{db.Cond{res.softDeleteColumn: db.IsNull()}}
+	case softDeleteScopeOnlyDeleted:
+		return []interface{}{db.Cond{res.softDeleteColumn: db.IsNotNull()}}
+	}
+	return nil
 }
 
 func filter(conds []interface{}) []interface{} {
@@ -99,6 +166,27 @@ func (r *Result) where(conds []interface{}) *Result {
 	})
 }
 
+func (r *Result) softDelete(column string) *Result {
+	return r.frame(func(res *result) error {
+		res.softDeleteColumn = column
+		return nil
+	})
+}
+
+func (r *Result) withScope(scope db.Cond) *Result {
+	return r.frame(func(res *result) error {
+		res.scope = scope
+		return nil
+	})
+}
+
+func (r *Result) withSession(sess Session) *Result {
+	return r.frame(func(res *result) error {
+		res.sess = sess
+		return nil
+	})
+}
+
 func (r *Result) setErr(err error) {
 	if err == nil {
 		return
@@ -128,6 +216,42 @@ func (r *Result) And(conds ...interface{}) db.Result {
 	})
 }
 
+// WithDeleted includes soft deleted items in the result set.
+func (r *Result) WithDeleted() db.Result {
+	return r.frame(func(res *result) error {
+		res.softDeleteScope = softDeleteScopeWithDeleted
+		return nil
+	})
+}
+
+// Unscoped lifts the conditions the collection was scoped with.
+func (r *Result) Unscoped() db.Result {
+	return r.frame(func(res *result) error {
+		res.unscoped = true
+		return nil
+	})
+}
+
+// StrictMapping overrides the strict mapping setting of the session for this
+// result set.
+func (r *Result) StrictMapping(value bool) db.Result {
+	return r.frame(func(res *result) error {
+		res.strictMapping = &value
+		return nil
+	})
+}
+
+// OnlyDeleted limits the result set to soft deleted items.
+func (r *Result) OnlyDeleted() db.Result {
+	return r.frame(func(res *result) error {
+		if res.softDeleteColumn == "" {
+			return db.ErrMissingSoftDeleteColumn
+		}
+		res.softDeleteScope = softDeleteScopeOnlyDeleted
+		return nil
+	})
+}
+
 // Limit determines the maximum limit of Results to be returned.
 func (r *Result) Limit(n int) db.Result {
 	return r.frame(func(res *result) error {
@@ -211,6 +335,17 @@ func (r *Result) Select(fields ...interface{}) db.Result {
 	})
 }
 
+// Distinct makes the result set return unique rows only.
+func (r *Result) Distinct(fields ...interface{}) db.Result {
+	return r.frame(func(res *result) error {
+		res.distinct = true
+		if len(fields) > 0 {
+			res.fields = fields
+		}
+		return nil
+	})
+}
+
 // String satisfies fmt.Stringer
 func (r *Result) String() string {
 	query, err := r.Paginator()
@@ -228,6 +363,12 @@ func (r *Result) All(dst interface{}) error {
 		return err
 	}
 	err = query.Iterator().All(dst)
+	if err == nil {
+		err = r.preloadRelations(dst)
+	}
+	if err == nil {
+		err = r.fetched(dst)
+	}
 	r.setErr(err)
 	return err
 }
@@ -242,10 +383,81 @@ func (r *Result) One(dst interface{}) error {
 	}
 
 	err = query.Iterator().One(dst)
+	if err == nil {
+		err = r.preloadRelations(dst)
+	}
+	if err == nil {
+		err = r.fetched(dst)
+	}
 	r.setErr(err)
 	return err
 }
 
+// Preload loads the given relations into the items fetched by All and One.
+func (r *Result) Preload(relations ...string) db.Result {
+	return r.frame(func(res *result) error {
+		res.preload = append(res.preload, relations...)
+		return nil
+	})
+}
+
+func (r *Result) preloadRelations(dst interface{}) error {
+	res, err := r.fastForward()
+	if err != nil {
+		return err
+	}
+	if len(res.preload) == 0 {
+		return nil
+	}
+	if res.sess == nil {
+		return db.ErrNotSupportedByAdapter
+	}
+	return preload.Load(res.sess, sqlbuilder.MapperOf(res.sess), dst, res.preload)
+}
+
+// fetched runs the AfterFind hooks of the records fetched by All, One and Next
+// and takes a snapshot of them, so only changed columns are updated when
+// they're saved.
+func (r *Result) fetched(dst interface{}) error {
+	res, err := r.fastForward()
+	if err != nil {
+		return err
+	}
+	if res.sess == nil || res.refresh {
+		return nil
+	}
+
+	err = eachRecord(dst, func(record db.Record) error {
+		return recordAfterFind(res.sess, record)
+	})
+	if err != nil {
+		return err
+	}
+
+	if tracker, ok := res.sess.(recordTracker); ok {
+		tracker.trackRecords(dst)
+	}
+	return nil
+}
+
+// forRefresh returns a result set that is used to read items back after
+// writing them, AfterFind hooks don't run for them and they're not tracked.
+func (r *Result) forRefresh() *Result {
+	return r.frame(func(res *result) error {
+		res.refresh = true
+		return nil
+	})
+}
+
+// refreshing returns the given result set as one that reads items back after
+// writing them, see Result.forRefresh.
+func refreshing(res db.Result) db.Result {
+	if r, ok := res.(*Result); ok {
+		return r.forRefresh()
+	}
+	return res
+}
+
 // Next fetches the next Result from the set.
 func (r *Result) Next(dst interface{}) bool {
 	r.iterMu.Lock()
@@ -261,6 +473,10 @@ func (r *Result) Next(dst interface{}) bool {
 	}
 
 	if r.iter.Next(dst) {
+		if err := r.fetched(dst); err != nil {
+			r.setErr(err)
+			return false
+		}
 		return true
 	}
 
@@ -274,17 +490,80 @@ func (r *Result) Next(dst interface{}) bool {
 
 // Delete deletes all matching items from the collection.
 func (r *Result) Delete() error {
-	query, err := r.buildDelete()
+	_, err := r.DeleteCount()
+	return err
+}
+
+// DeleteCount deletes all matching items from the collection and returns how
+// many were deleted.
+func (r *Result) DeleteCount() (uint64, error) {
+	res, err := r.fastForward()
 	if err != nil {
 		r.setErr(err)
-		return err
+		return 0, err
 	}
+	if res.softDeleteColumn != "" {
+		return r.softDeleteItems(res.softDeleteColumn, res.now())
+	}
+	return r.HardDeleteCount()
+}
 
-	_, err = query.Exec()
-	r.setErr(err)
+// HardDelete removes all matching items from the collection, even if the
+// collection soft deletes items.
+func (r *Result) HardDelete() error {
+	_, err := r.HardDeleteCount()
 	return err
 }
 
+// HardDeleteCount removes all matching items from the collection and returns
+// how many were removed, see HardDelete.
+func (r *Result) HardDeleteCount() (uint64, error) {
+	query, err := r.buildDelete()
+	if err != nil {
+		r.setErr(err)
+		return 0, err
+	}
+
+	return r.rowsAffected(query.Exec())
+}
+
+func (r *Result) softDeleteItems(column string, deletedAt time.Time) (uint64, error) {
+	query, err := r.buildUpdate(map[string]interface{}{column: deletedAt})
+	if err != nil {
+		r.setErr(err)
+		return 0, err
+	}
+
+	// Items that were already deleted keep their original deletion time.
+	return r.rowsAffected(query.And(db.Cond{column: db.IsNull()}).Exec())
+}
+
+// rowsAffected returns the number of rows affected by a statement.
+func (r *Result) rowsAffected(res sql.Result, err error) (uint64, error) {
+	if err != nil {
+		r.setErr(err)
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		r.setErr(err)
+		return 0, err
+	}
+	return uint64(n), nil
+}
+
+// Explain describes the execution plan of the query that fetches the result
+// set.
+func (r *Result) Explain(ctx context.Context, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	query, err := r.Paginator()
+	if err != nil {
+		r.setErr(err)
+		return nil, err
+	}
+	return query.Explain(ctx, opts)
+}
+
 // Close closes the Result set.
 func (r *Result) Close() error {
 	if r.iter != nil {
@@ -298,15 +577,34 @@ func (r *Result) Close() error {
 // Update updates matching items from the collection with values of the given
 // map or struct.
 func (r *Result) Update(values interface{}) error {
+	_, err := r.UpdateCount(values)
+	return err
+}
+
+// Increment adds n to the given column of the matching items.
+func (r *Result) Increment(column string, n interface{}) error {
+	return r.Update(map[string]interface{}{
+		column: db.Raw("? + ?", db.Column(column), n),
+	})
+}
+
+// Decrement subtracts n from the given column of the matching items.
+func (r *Result) Decrement(column string, n interface{}) error {
+	return r.Update(map[string]interface{}{
+		column: db.Raw("? - ?", db.Column(column), n),
+	})
+}
+
+// UpdateCount updates matching items from the collection and returns how many
+// were updated.
+func (r *Result) UpdateCount(values interface{}) (uint64, error) {
 	query, err := r.buildUpdate(values)
 	if err != nil {
 		r.setErr(err)
-		return err
+		return 0, err
 	}
 
-	_, err = query.Exec()
-	r.setErr(err)
-	return err
+	return r.rowsAffected(query.Exec())
 }
 
 func (r *Result) TotalPages() (uint, error) {
@@ -343,7 +641,7 @@ func (r *Result) TotalEntries() (uint64, error) {
 
 // Exists returns true if at least one item on the collection exists.
 func (r *Result) Exists() (bool, error) {
-	query, err := r.buildCount()
+	query, err := r.buildAggregate(db.Raw("count(1) AS _t"))
 	if err != nil {
 		r.setErr(err)
 		return false, err
@@ -372,7 +670,7 @@ func (r *Result) Exists() (bool, error) {
 
 // Count counts the elements on the set.
 func (r *Result) Count() (uint64, error) {
-	query, err := r.buildCount()
+	query, err := r.buildAggregate(db.Raw("count(1) AS _t"))
 	if err != nil {
 		r.setErr(err)
 		return 0, err
@@ -392,25 +690,83 @@ func (r *Result) Count() (uint64, error) {
 	return counter.Count, nil
 }
 
-func (r *Result) Paginator() (db.Paginator, error) {
-	if err := r.Err(); err != nil {
-		return nil, err
+// Sum stores the sum of the values of the given column into dst.
+func (r *Result) Sum(column string, dst interface{}) error {
+	return r.aggregate("SUM", column, dst)
+}
+
+// Avg returns the average of the values of the given column.
+func (r *Result) Avg(column string) (float64, error) {
+	var avg float64
+	if err := r.aggregate("AVG", column, &avg); err != nil {
+		return 0, err
 	}
+	return avg, nil
+}
 
-	res, err := r.fastForward()
+// Min stores the smallest value of the given column into dst.
+func (r *Result) Min(column string, dst interface{}) error {
+	return r.aggregate("MIN", column, dst)
+}
+
+// Max stores the largest value of the given column into dst.
+func (r *Result) Max(column string, dst interface{}) error {
+	return r.aggregate("MAX", column, dst)
+}
+
+func (r *Result) aggregate(fn string, column string, dst interface{}) error {
+	dstV := reflect.ValueOf(dst)
+	if dstV.Kind() != reflect.Ptr || dstV.IsNil() {
+		return sqlbuilder.ErrExpectingPointer
+	}
+
+	query, err := r.buildAggregate(db.Raw(fn+"(?) AS _t", db.Column(column)))
 	if err != nil {
-		return nil, err
+		r.setErr(err)
+		return err
 	}
 
-	sel := r.SQL().Select(res.fields...).
-		From(res.table).
-		Limit(res.limit).
-		Offset(res.offset).
-		GroupBy(res.groupBy...).
-		OrderBy(res.orderBy...)
+	// Aggregate functions return NULL when there are no values, the value is
+	// scanned into a pointer that is left nil in that case.
+	if dstV.Elem().Kind() == reflect.Ptr {
+		if err := query.One(dst); err != nil && !errors.Is(err, db.ErrNoMoreRows) {
+			r.setErr(err)
+			return err
+		}
+		return nil
+	}
 
-	for i := range res.conds {
-		sel = sel.And(filter(res.conds[i])...)
+	value := reflect.New(dstV.Type())
+	if err := query.One(value.Interface()); err != nil && !errors.Is(err, db.ErrNoMoreRows) {
+		r.setErr(err)
+		return err
+	}
+
+	if value.Elem().IsNil() {
+		dstV.Elem().Set(reflect.Zero(dstV.Elem().Type()))
+	} else {
+		dstV.Elem().Set(value.Elem().Elem())
+	}
+	return nil
+}
+
+// Pluck dumps the values of the given column into a pointer to a slice.
+func (r *Result) Pluck(column string, dst interface{}) error {
+	query, err := r.Select(column).(*Result).Paginator()
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	err = query.Iterator().All(dst)
+	r.setErr(err)
+	return err
+}
+
+func (r *Result) Paginator() (db.Paginator, error) {
+	sel, res, err := r.buildSelect()
+	if err != nil {
+		return nil, err
 	}
 
 	pag := sel.Paginate(res.pageSize).
@@ -428,6 +784,79 @@ func (r *Result) Paginator() (db.Paginator, error) {
 	return pag, nil
 }
 
+// Chunk walks the result set in chunks of at most size items.
+func (r *Result) Chunk(ctx context.Context, size uint, fn db.ChunkFunc, opts *db.ChunkOptions) error {
+	sel, res, err := r.buildSelect()
+	if err != nil {
+		r.setErr(err)
+		return err
+	}
+
+	chunkOpts := db.ChunkOptions{}
+	if opts != nil {
+		chunkOpts = *opts
+	}
+	// Result sets that are ordered are paginated in their own order.
+	if chunkOpts.Key == "" && len(res.orderBy) == 0 && res.sess != nil {
+		pks, err := res.sess.PrimaryKeys(res.table)
+		if err != nil {
+			r.setErr(err)
+			return err
+		}
+		if len(pks) == 1 {
+			chunkOpts.Key = pks[0]
+		}
+	}
+
+	err = sel.Chunk(ctx, size, func(sess db.Session, batch interface{}) error {
+		if err := r.preloadRelations(batch); err != nil {
+			return err
+		}
+		if err := r.fetched(batch); err != nil {
+			return err
+		}
+		return fn(sess, batch)
+	}, &chunkOpts)
+	r.setErr(err)
+	return err
+}
+
+// buildSelect returns a query that fetches the items of the result set,
+// pagination settings aside.
+func (r *Result) buildSelect() (db.Selector, *result, error) {
+	if err := r.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	res, err := r.fastForward()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sel := r.SQL().Select(res.fields...)
+	if res.distinct {
+		sel = r.SQL().Select().Distinct(res.fields...)
+	}
+
+	sel = sel.From(res.table).
+		Limit(res.limit).
+		Offset(res.offset).
+		GroupBy(res.groupBy...).
+		OrderBy(res.orderBy...)
+
+	for i := range res.conds {
+		sel = sel.And(filter(res.conds[i])...)
+	}
+	if conds := res.implicitConds(); len(conds) > 0 {
+		sel = sel.And(conds...)
+	}
+	if res.strictMapping != nil {
+		sel = sel.StrictMapping(*res.strictMapping)
+	}
+
+	return sel, res, nil
+}
+
 func (r *Result) buildDelete() (db.Deleter, error) {
 	if err := r.Err(); err != nil {
 		return nil, err
@@ -444,6 +873,9 @@ func (r *Result) buildDelete() (db.Deleter, error) {
 	for i := range res.conds {
 		del = del.And(filter(res.conds[i])...)
 	}
+	if conds := res.implicitConds(); len(conds) > 0 {
+		del = del.And(conds...)
+	}
 
 	return del, nil
 }
@@ -459,17 +891,22 @@ func (r *Result) buildUpdate(values interface{}) (db.Updater, error) {
 	}
 
 	upd := r.SQL().Update(res.table).
-		Set(values).
+		Set(withAutoUpdateTimestamps(sqlbuilder.MapperOf(res.sess), values, res.now())).
 		Limit(res.limit)
 
 	for i := range res.conds {
 		upd = upd.And(filter(res.conds[i])...)
 	}
+	if conds := res.implicitConds(); len(conds) > 0 {
+		upd = upd.And(conds...)
+	}
 
 	return upd, nil
 }
 
-func (r *Result) buildCount() (db.Selector, error) {
+// buildAggregate returns a query that computes the given expression over the
+// items of the result set.
+func (r *Result) buildAggregate(expr interface{}) (db.Selector, error) {
 	if err := r.Err(); err != nil {
 		return nil, err
 	}
@@ -479,13 +916,25 @@ func (r *Result) buildCount() (db.Selector, error) {
 		return nil, err
 	}
 
-	sel := r.SQL().Select(db.Raw("count(1) AS _t")).
-		From(res.table).
+	sel := r.SQL().Select(expr)
+	if res.distinct {
+		sel = r.SQL().Select().Distinct(res.fields...)
+	}
+
+	sel = sel.From(res.table).
 		GroupBy(res.groupBy...)
 
 	for i := range res.conds {
 		sel = sel.And(filter(res.conds[i])...)
 	}
+	if conds := res.implicitConds(); len(conds) > 0 {
+		sel = sel.And(conds...)
+	}
+
+	if res.distinct {
+		// Duplicated rows are discarded before aggregating them.
+		sel = r.SQL().Select(expr).From(db.Raw("? AS _d", sel))
+	}
 
 	return sel, nil
 }
@@ -0,0 +1,99 @@
+package sqladapter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// scopeValues returns the columns of the scope that are compared to plain
+// values, like {"tenant_id": 1}, and their values.
+func scopeValues(scope db.Cond) map[string]interface{} {
+	values := map[string]interface{}{}
+	for key, value := range scope {
+		column, ok := key.(string)
+		if !ok || strings.ContainsAny(column, " =<>!()") || value == nil {
+			continue
+		}
+		switch value.(type) {
+		case *db.Comparison, db.LogicalExpr, *db.RawExpr, []byte:
+			continue
+		}
+		if kind := reflect.TypeOf(value).Kind(); kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+			continue
+		}
+		values[column] = value
+	}
+	return values
+}
+
+// withScopeValues returns the item with the plain values of the scope set, so
+// it's part of the scope once it's inserted. Pointers to structs are modified
+// in place, other items are converted into maps.
+func withScopeValues(mapper *reflectx.Mapper, item interface{}, scope db.Cond) (interface{}, error) {
+	values := scopeValues(scope)
+	if len(values) == 0 {
+		return item, nil
+	}
+
+	itemV := reflect.ValueOf(item)
+	if itemV.Kind() == reflect.Ptr && !itemV.IsNil() && itemV.Elem().Kind() == reflect.Struct {
+		fields := mapper.TypeMap(itemV.Elem().Type()).Names
+
+		missing := false
+		for column, value := range values {
+			fi, ok := fields[column]
+			if !ok || !setFieldValue(reflectx.FieldByIndexes(itemV.Elem(), fi.Index), value) {
+				missing = true
+			}
+		}
+		if !missing {
+			return item, nil
+		}
+	}
+
+	columns, columnValues, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{Mapper: mapper})
+	if err != nil {
+		return nil, err
+	}
+	if columns == nil && itemV.IsValid() {
+		return nil, fmt.Errorf("upper: could not apply scope to %T", item)
+	}
+
+	mapped := make(map[string]interface{}, len(columns)+len(values))
+	for i := range columns {
+		mapped[columns[i]] = columnValues[i]
+	}
+	for column, value := range values {
+		mapped[column] = value
+	}
+	return mapped, nil
+}
+
+// setFieldValue sets field to value, converting it if needed. False is
+// returned if value can't be assigned to the field.
+func setFieldValue(field reflect.Value, value interface{}) bool {
+	valueV := reflect.ValueOf(value)
+	if field.Kind() == reflect.Ptr && valueV.Kind() != reflect.Ptr {
+		if !valueV.Type().ConvertibleTo(field.Type().Elem()) {
+			return false
+		}
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(valueV.Convert(field.Type().Elem()))
+		field.Set(ptr)
+		return true
+	}
+	switch {
+	case valueV.Type().AssignableTo(field.Type()):
+		field.Set(valueV)
+	case valueV.Type().ConvertibleTo(field.Type()):
+		field.Set(valueV.Convert(field.Type()))
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package sqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+type scopedItem struct {
+	ID       uint64 `db:"id,omitempty"`
+	Name     string `db:"name"`
+	TenantID *int64 `db:"tenant_id"`
+}
+
+func TestScopeValues(t *testing.T) {
+	values := scopeValues(db.Cond{
+		"tenant_id":  1,
+		"name":       db.Eq("foo"),
+		"id >":       10,
+		"deleted_at": nil,
+		"kind":       []string{"a", "b"},
+	})
+	assert.Equal(t, map[string]interface{}{"tenant_id": 1}, values)
+}
+
+func TestWithScopeValues(t *testing.T) {
+	scope := db.Cond{"tenant_id": 1}
+
+	item := scopedItem{Name: "foo"}
+	scoped, err := withScopeValues(sqlbuilder.Mapper, &item, scope)
+	assert.NoError(t, err)
+	assert.Equal(t, &item, scoped)
+	if assert.NotNil(t, item.TenantID) {
+		assert.Equal(t, int64(1), *item.TenantID)
+	}
+
+	scoped, err = withScopeValues(sqlbuilder.Mapper, scopedItem{Name: "bar"}, scope)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "bar", "tenant_id": 1}, scoped)
+
+	scoped, err = withScopeValues(sqlbuilder.Mapper, map[string]interface{}{"name": "baz"}, scope)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "baz", "tenant_id": 1}, scoped)
+}
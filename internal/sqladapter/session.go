@@ -16,6 +16,7 @@ import (
 
 	db "github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/cache"
+	"github.com/upper/db/v4/internal/reflectx"
 	"github.com/upper/db/v4/internal/sqladapter/compat"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
 	"github.com/upper/db/v4/internal/sqlbuilder"
@@ -27,7 +28,6 @@ var (
 )
 
 var (
-	slowQueryThreshold          = time.Millisecond * 200
 	retryTransactionWaitTime    = time.Millisecond * 10
 	retryTransactionMaxWaitTime = time.Second * 1
 )
@@ -49,6 +49,12 @@ type statementCompiler interface {
 	CompileStatement(sess Session, stmt *exql.Statement, args []interface{}) (string, []interface{}, error)
 }
 
+// statementExplainer allows the adapter to describe the execution plan of a
+// query. The query is given with its original placeholders and arguments.
+type statementExplainer interface {
+	ExplainStatement(sess Session, ctx context.Context, query string, args []interface{}, opts *db.ExplainOptions) (*db.QueryPlan, error)
+}
+
 // sessValueConverter converts values before being passed to the underlying driver.
 type sessValueConverter interface {
 	ConvertValue(in interface{}) interface{}
@@ -67,6 +73,12 @@ type valueConverter interface {
 	}
 }
 
+// timeNormalizer normalizes the automatic timestamps set by the session, like
+// converting them to UTC or truncating them to the precision of the database.
+type timeNormalizer interface {
+	NormalizeTime(time.Time) time.Time
+}
+
 // errorConverter converts an error value from the underlying driver into
 // something different.
 type errorConverter interface {
@@ -140,6 +152,14 @@ type Session interface {
 
 	Delete(db.Record) error
 
+	CreateAll(interface{}) error
+
+	SaveAll(interface{}) error
+
+	DeleteAll(interface{}) error
+
+	Changed(db.Record) []string
+
 	// WaitForConnection attempts to run the given connection function a fixed
 	// number of times before failing.
 	WaitForConnection(func() error) error
@@ -179,6 +199,21 @@ type Session interface {
 
 	Rollback() error
 
+	// Stats returns a snapshot of the metrics collected by the session.
+	Stats() db.Stats
+
+	// Now returns the current time according to the session's clock,
+	// normalized by the adapter.
+	Now() time.Time
+
+	// SetStatsCollector sets a collector that receives metrics as soon as
+	// they're recorded by the session.
+	SetStatsCollector(db.StatsCollector)
+
+	// FieldMapper returns the mapper that maps the fields of structs to
+	// columns, as configured by SetFieldMapping.
+	FieldMapper() *reflectx.Mapper
+
 	db.Settings
 }
 
@@ -186,16 +221,19 @@ type Session interface {
 func NewTx(adapter AdapterSession, tx *sql.Tx) (Session, error) {
 	sessTx := &sessionWithContext{
 		session: &session{
-			Settings: db.DefaultSettings,
+			Settings: db.NewSettings(),
 
 			sqlTx:             tx,
 			adapter:           adapter,
 			cachedPKs:         cache.NewCache(),
 			cachedCollections: cache.NewCache(),
 			cachedStatements:  cache.NewCache(),
+			txStatements:      cache.NewCache(),
+			stats:             newStatsRecorder(),
 		},
 		ctx: context.Background(),
 	}
+	sessTx.watchStatementEvictions()
 	return sessTx, nil
 }
 
@@ -203,16 +241,19 @@ func NewTx(adapter AdapterSession, tx *sql.Tx) (Session, error) {
 func NewSession(connURL db.ConnectionURL, adapter AdapterSession) Session {
 	sess := &sessionWithContext{
 		session: &session{
-			Settings: db.DefaultSettings,
+			Settings: db.NewSettings(),
 
 			connURL:           connURL,
 			adapter:           adapter,
 			cachedPKs:         cache.NewCache(),
 			cachedCollections: cache.NewCache(),
 			cachedStatements:  cache.NewCache(),
+			txStatements:      cache.NewCache(),
+			stats:             newStatsRecorder(),
 		},
 		ctx: context.Background(),
 	}
+	sess.watchStatementEvictions()
 	return sess
 }
 
@@ -242,6 +283,22 @@ type session struct {
 	cachedStatements  *cache.Cache
 	cachedCollections *cache.Cache
 
+	// txStatements holds statements that are bound to sqlTx, they're created
+	// from the ones in cachedStatements.
+	txStatements *cache.Cache
+
+	trackedRecordsMu sync.Mutex // guards trackedRecords
+
+	// trackedRecords holds the records tracked within a transaction along
+	// with the snapshots they had before, which are restored on rollback.
+	trackedRecords []recordSnapshot
+
+	stats *statsRecorder
+
+	// mapper is built from the field mapping setting, it's shared with the
+	// transactions of the session so they use the same type cache.
+	mapper atomic.Pointer[reflectx.Mapper]
+
 	template *exql.Template
 }
 
@@ -278,6 +335,11 @@ func (sess *sessionWithContext) SQL() db.SQL {
 }
 
 func (sess *sessionWithContext) Err(errIn error) (errOur error) {
+	var dbErr *db.DatabaseError
+	if errors.As(errIn, &dbErr) {
+		// Already converted.
+		return errIn
+	}
 	if convertError, ok := sess.adapter.(errorConverter); ok {
 		return convertError.Err(errIn)
 	}
@@ -388,31 +450,13 @@ func (sess *sessionWithContext) Save(record db.Record) error {
 		return db.ErrExpectingPointerToStruct
 	}
 
-	store := record.Store(sess)
-	if saver, ok := store.(db.StoreSaver); ok {
-		return saver.Save(record)
-	}
-
-	id := db.Cond{}
-	keys, values, err := recordPrimaryKeyFieldValues(store, record)
-	if err != nil {
+	if err := recordBeforeSave(sess, record); err != nil {
 		return err
 	}
-	for i := range values {
-		if values[i] != reflect.Zero(reflect.TypeOf(values[i])).Interface() {
-			id[keys[i]] = values[i]
-		}
-	}
-
-	if len(id) > 0 && len(id) == len(values) {
-		// check if record exists before updating it
-		exists, _ := store.Find(id).Count()
-		if exists > 0 {
-			return recordUpdate(store, record)
-		}
+	if err := recordSave(record.Store(sess), record); err != nil {
+		return err
 	}
-
-	return recordCreate(store, record)
+	return recordAfterSave(sess, record)
 }
 
 func (sess *sessionWithContext) Delete(record db.Record) error {
@@ -426,10 +470,8 @@ func (sess *sessionWithContext) Delete(record db.Record) error {
 
 	store := record.Store(sess)
 
-	if hook, ok := record.(db.BeforeDeleteHook); ok {
-		if err := hook.BeforeDelete(sess); err != nil {
-			return err
-		}
+	if err := recordBeforeDelete(sess, record); err != nil {
+		return err
 	}
 
 	if deleter, ok := store.(db.StoreDeleter); ok {
@@ -441,18 +483,16 @@ func (sess *sessionWithContext) Delete(record db.Record) error {
 		if err != nil {
 			return err
 		}
-		if err := store.Find(conds).Delete(); err != nil {
-			return err
-		}
-	}
-
-	if hook, ok := record.(db.AfterDeleteHook); ok {
-		if err := hook.AfterDelete(sess); err != nil {
+		if column := recordSoftDeleteColumn(store); column != "" {
+			if err := recordSoftDelete(store, []db.Record{record}, column, conds); err != nil {
+				return err
+			}
+		} else if err := store.Find(conds).Delete(); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return recordAfterDelete(sess, record)
 }
 
 func (sess *sessionWithContext) DB() *sql.DB {
@@ -498,13 +538,20 @@ func (sess *sessionWithContext) BindTx(ctx context.Context, tx *sql.Tx) error {
 
 func (sess *sessionWithContext) Commit() error {
 	if sess.sqlTx != nil {
-		return sess.sqlTx.Commit()
+		defer sess.txStatements.Clear()
+		if err := sess.sqlTx.Commit(); err != nil {
+			return err
+		}
+		sess.commitTrackedRecords()
+		return nil
 	}
 	return db.ErrNotWithinTransaction
 }
 
 func (sess *sessionWithContext) Rollback() error {
 	if sess.sqlTx != nil {
+		defer sess.txStatements.Clear()
+		defer sess.rollbackTrackedRecords()
 		return sess.sqlTx.Rollback()
 	}
 	return db.ErrNotWithinTransaction
@@ -583,6 +630,50 @@ func (sess *sessionWithContext) SetMaxOpenConns(n int) {
 	}
 }
 
+// SetFieldMapping sets how the fields of structs are mapped to columns.
+func (sess *sessionWithContext) SetFieldMapping(mapping db.FieldMapping) {
+	sess.Settings.SetFieldMapping(mapping)
+	sess.mapper.Store(sqlbuilder.NewMapper(mapping))
+}
+
+// FieldMapper returns the mapper that maps the fields of structs to columns.
+func (sess *sessionWithContext) FieldMapper() *reflectx.Mapper {
+	if mapper := sess.mapper.Load(); mapper != nil {
+		return mapper
+	}
+	mapper := sqlbuilder.NewMapper(sess.FieldMapping())
+	if sess.mapper.CompareAndSwap(nil, mapper) {
+		return mapper
+	}
+	return sess.mapper.Load()
+}
+
+// Now returns the current time according to the session's clock, normalized
+// by the adapter.
+func (sess *sessionWithContext) Now() time.Time {
+	now := sess.Clock()()
+	if normalizer, ok := sess.adapter.(timeNormalizer); ok {
+		return normalizer.NormalizeTime(now)
+	}
+	return now
+}
+
+// Stats returns a snapshot of the metrics collected by the session and its
+// transactions.
+func (sess *sessionWithContext) Stats() db.Stats {
+	stats := sess.stats.snapshot()
+	if sqlDB := sess.DB(); sqlDB != nil {
+		stats.DB = sqlDB.Stats()
+	}
+	return stats
+}
+
+// SetStatsCollector sets a collector that receives metrics as soon as they're
+// recorded by the session.
+func (sess *sessionWithContext) SetStatsCollector(collector db.StatsCollector) {
+	sess.stats.setCollector(collector)
+}
+
 // Reset removes all caches.
 func (sess *sessionWithContext) Reset() {
 	sess.cacheMu.Lock()
@@ -591,6 +682,7 @@ func (sess *sessionWithContext) Reset() {
 	sess.cachedPKs.Clear()
 	sess.cachedCollections.Clear()
 	sess.cachedStatements.Clear()
+	sess.txStatements.Clear()
 
 	if sess.template != nil {
 		sess.template.Cache.Clear()
@@ -604,6 +696,8 @@ func (sess *sessionWithContext) NewClone(adapter AdapterSession, checkConn bool)
 	newSess.name = sess.name
 	newSess.sqlDB = sess.sqlDB
 	newSess.cachedPKs = sess.cachedPKs
+	newSess.cachedStatements = sess.cachedStatements
+	newSess.stats = sess.stats
 
 	if checkConn {
 		if err := newSess.Ping(); err != nil {
@@ -616,6 +710,7 @@ func (sess *sessionWithContext) NewClone(adapter AdapterSession, checkConn bool)
 
 	// New transaction should inherit parent settings
 	copySettings(sess, newSess)
+	newSess.mapper.Store(sess.FieldMapper())
 
 	return newSess, nil
 }
@@ -633,9 +728,11 @@ func (sess *sessionWithContext) Close() error {
 	}
 
 	sess.cachedCollections.Clear()
-	sess.cachedStatements.Clear() // Closes prepared statements as well.
+	sess.txStatements.Clear()
 
 	if !sess.IsTransaction() {
+		sess.cachedStatements.Clear() // Closes prepared statements as well.
+
 		if cleaner, ok := sess.adapter.(hasCleanUp); ok {
 			if err := cleaner.CleanUp(); err != nil {
 				return err
@@ -666,28 +763,40 @@ func (sess *sessionWithContext) Collection(name string) db.Collection {
 	}
 }
 
-func queryLog(status *db.QueryStatus) {
+func queryLog(settings db.Settings, status *db.QueryStatus) {
 	diff := status.End.Sub(status.Start)
 
 	slowQuery := false
-	if diff >= slowQueryThreshold {
+	if threshold := settings.SlowQueryThreshold(); threshold > 0 && diff >= threshold {
 		status.Err = db.ErrWarnSlowQuery
 		slowQuery = true
 	}
 
+	if !settings.QueryArgsLoggingEnabled() {
+		status.Args = nil
+	}
+	status.SkipStack = !settings.QueryStackCaptureEnabled()
+
 	if status.Err != nil || slowQuery {
-		db.LC().Warn(status)
+		if settings.QueryLogLevel() <= db.LogLevelWarn {
+			db.LC().Warn(status)
+		}
 		return
 	}
 
-	db.LC().Debug(status)
+	if settings.QueryLogLevel() <= db.LogLevelDebug {
+		db.LC().Debug(status)
+	}
 }
 
 func (sess *sessionWithContext) StatementPrepare(ctx context.Context, stmt *exql.Statement) (sqlStmt *sql.Stmt, err error) {
 	var query string
 
 	defer func(start time.Time) {
-		queryLog(&db.QueryStatus{
+		if err != nil {
+			err = sess.Err(err)
+		}
+		queryLog(sess, &db.QueryStatus{
 			TxID:     sess.txID,
 			SessID:   sess.sessID,
 			RawQuery: query,
@@ -740,6 +849,9 @@ func (sess *sessionWithContext) StatementExec(ctx context.Context, stmt *exql.St
 	var query string
 
 	defer func(start time.Time) {
+		if err != nil {
+			err = sess.Err(err)
+		}
 		status := db.QueryStatus{
 			TxID:     sess.txID,
 			SessID:   sess.sessID,
@@ -751,6 +863,8 @@ func (sess *sessionWithContext) StatementExec(ctx context.Context, stmt *exql.St
 			Context:  ctx,
 		}
 
+		sess.stats.observeQuery(stmt, &status)
+
 		if res != nil {
 			if rowsAffected, err := res.RowsAffected(); err == nil {
 				status.RowsAffected = &rowsAffected
@@ -761,7 +875,7 @@ func (sess *sessionWithContext) StatementExec(ctx context.Context, stmt *exql.St
 			}
 		}
 
-		queryLog(&status)
+		queryLog(sess, &status)
 	}(time.Now())
 
 	if execer, ok := sess.adapter.(statementExecer); ok {
@@ -774,7 +888,7 @@ func (sess *sessionWithContext) StatementExec(ctx context.Context, stmt *exql.St
 	}
 
 	tx := sess.Transaction()
-	if sess.Settings.PreparedStatementCacheEnabled() && tx == nil {
+	if sess.Settings.PreparedStatementCacheEnabled() {
 		var p *Stmt
 		if p, query, args, err = sess.prepareStatement(ctx, stmt, args); err != nil {
 			return nil, err
@@ -804,6 +918,9 @@ func (sess *sessionWithContext) StatementQuery(ctx context.Context, stmt *exql.S
 	var query string
 
 	defer func(start time.Time) {
+		if err != nil {
+			err = sess.Err(err)
+		}
 		status := db.QueryStatus{
 			TxID:     sess.txID,
 			SessID:   sess.sessID,
@@ -814,12 +931,13 @@ func (sess *sessionWithContext) StatementQuery(ctx context.Context, stmt *exql.S
 			End:      time.Now(),
 			Context:  ctx,
 		}
-		queryLog(&status)
+		sess.stats.observeQuery(stmt, &status)
+		queryLog(sess, &status)
 	}(time.Now())
 
 	tx := sess.Transaction()
 
-	if sess.Settings.PreparedStatementCacheEnabled() && tx == nil {
+	if sess.Settings.PreparedStatementCacheEnabled() {
 		var p *Stmt
 		if p, query, args, err = sess.prepareStatement(ctx, stmt, args); err != nil {
 			return nil, err
@@ -843,12 +961,34 @@ func (sess *sessionWithContext) StatementQuery(ctx context.Context, stmt *exql.S
 	return
 }
 
+// StatementExplain compiles a statement and asks the adapter to describe its
+// execution plan.
+func (sess *sessionWithContext) StatementExplain(ctx context.Context, stmt *exql.Statement, opts *db.ExplainOptions, args ...interface{}) (*db.QueryPlan, error) {
+	explainer, ok := sess.adapter.(statementExplainer)
+	if !ok {
+		return nil, db.ErrNotSupportedByAdapter
+	}
+
+	query, err := stmt.Compile(sess.adapter.Template())
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &db.ExplainOptions{}
+	}
+
+	return explainer.ExplainStatement(sess, ctx, query, args, opts)
+}
+
 // StatementQueryRow compiles and executes a statement that returns at most one
 // row.
 func (sess *sessionWithContext) StatementQueryRow(ctx context.Context, stmt *exql.Statement, args ...interface{}) (row *sql.Row, err error) {
 	var query string
 
 	defer func(start time.Time) {
+		if err != nil {
+			err = sess.Err(err)
+		}
 		status := db.QueryStatus{
 			TxID:     sess.txID,
 			SessID:   sess.sessID,
@@ -859,12 +999,13 @@ func (sess *sessionWithContext) StatementQueryRow(ctx context.Context, stmt *exq
 			End:      time.Now(),
 			Context:  ctx,
 		}
-		queryLog(&status)
+		sess.stats.observeQuery(stmt, &status)
+		queryLog(sess, &status)
 	}(time.Now())
 
 	tx := sess.Transaction()
 
-	if sess.Settings.PreparedStatementCacheEnabled() && tx == nil {
+	if sess.Settings.PreparedStatementCacheEnabled() {
 		var p *Stmt
 		if p, query, args, err = sess.prepareStatement(ctx, stmt, args); err != nil {
 			return nil, err
@@ -914,7 +1055,9 @@ func (sess *sessionWithContext) compileStatement(stmt *exql.Statement, args []in
 }
 
 // prepareStatement compiles a query and tries to use previously generated
-// statement.
+// statement. Within a transaction, statements are prepared on the *sql.DB and
+// then bound to the transaction, so they can be reused by other transactions
+// as well.
 func (sess *sessionWithContext) prepareStatement(ctx context.Context, stmt *exql.Statement, args []interface{}) (*Stmt, string, []interface{}, error) {
 	sess.sqlDBMu.Lock()
 	defer sess.sqlDBMu.Unlock()
@@ -924,39 +1067,108 @@ func (sess *sessionWithContext) prepareStatement(ctx context.Context, stmt *exql
 		return nil, "", nil, db.ErrNotConnected
 	}
 
-	pc, ok := sess.cachedStatements.ReadRaw(stmt)
-	if ok {
-		// The statement was cachesess.
-		ps, err := pc.(*Stmt).Open()
-		if err == nil {
-			_, args, err = sess.compileStatement(stmt, args)
-			if err != nil {
-				return nil, "", nil, err
-			}
-			return ps, ps.query, args, nil
-		}
-	}
-
 	query, args, err := sess.compileStatement(stmt, args)
 	if err != nil {
 		return nil, "", nil, err
 	}
-	sqlStmt, err := func(query *string) (*sql.Stmt, error) {
-		if tx != nil {
-			return compat.PrepareContext(tx, ctx, *query)
-		}
-		return compat.PrepareContext(sess.sqlDB, ctx, *query)
-	}(&query)
+
+	var p *Stmt
+	if tx != nil {
+		p, err = sess.prepareTxStatement(ctx, tx, stmt, query)
+	} else {
+		p, err = sess.prepareDBStatement(ctx, stmt, query)
+	}
 	if err != nil {
-		return nil, "", nil, err
+		return nil, query, args, err
+	}
+	return p, p.query, args, nil
+}
+
+func (sess *sessionWithContext) prepareDBStatement(ctx context.Context, stmt *exql.Statement, query string) (*Stmt, error) {
+	statements := sess.statementCache(sess.cachedStatements)
+
+	if p, ok := openCachedStatement(statements, stmt); ok {
+		sess.stats.observeStatementCache(true)
+		return p, nil
+	}
+	sess.stats.observeStatementCache(false)
+
+	sqlStmt, err := compat.PrepareContext(sess.sqlDB, ctx, query)
+	if err != nil {
+		return nil, err
 	}
 
 	p, err := NewStatement(sqlStmt, query).Open()
 	if err != nil {
-		return nil, query, args, err
+		return nil, err
 	}
-	sess.cachedStatements.Write(stmt, p)
-	return p, p.query, args, nil
+	statements.Write(stmt, p)
+	return p, nil
+}
+
+func (sess *sessionWithContext) prepareTxStatement(ctx context.Context, tx *sql.Tx, stmt *exql.Statement, query string) (*Stmt, error) {
+	statements := sess.statementCache(sess.txStatements)
+
+	if p, ok := openCachedStatement(statements, stmt); ok {
+		sess.stats.observeStatementCache(true)
+		return p, nil
+	}
+
+	var p *Stmt
+	if sess.sqlDB == nil {
+		// The transaction was bound without a *sql.DB, statements can only be
+		// prepared on the transaction itself.
+		sess.stats.observeStatementCache(false)
+
+		sqlStmt, err := compat.PrepareContext(tx, ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		p = NewStatement(sqlStmt, query)
+	} else {
+		parent, err := sess.prepareDBStatement(ctx, stmt, query)
+		if err != nil {
+			return nil, err
+		}
+		p = newTxStatement(tx.StmtContext(ctx, parent.Stmt), parent)
+	}
+
+	p, err := p.Open()
+	if err != nil {
+		return nil, err
+	}
+	statements.Write(stmt, p)
+	return p, nil
+}
+
+// statementCache applies the current prepared statement cache settings to the
+// given cache.
+func (sess *sessionWithContext) statementCache(statements *cache.Cache) *cache.Cache {
+	_ = statements.SetCapacity(sess.PreparedStatementCacheCapacity())
+	statements.SetMaxIdleTime(sess.PreparedStatementCacheMaxIdleTime())
+	return statements
+}
+
+// watchStatementEvictions counts statements evicted from the session caches.
+func (sess *sessionWithContext) watchStatementEvictions() {
+	s := sess.session
+	onEvict := func(interface{}) {
+		s.stats.observeStatementCacheEviction()
+	}
+	s.cachedStatements.SetEvictionHandler(onEvict)
+	s.txStatements.SetEvictionHandler(onEvict)
+}
+
+func openCachedStatement(statements *cache.Cache, stmt *exql.Statement) (*Stmt, bool) {
+	pc, ok := statements.ReadRaw(stmt)
+	if !ok {
+		return nil, false
+	}
+	p, err := pc.(*Stmt).Open()
+	if err != nil {
+		return nil, false
+	}
+	return p, true
 }
 
 var waitForConnMu sync.Mutex
@@ -1037,10 +1249,20 @@ func ReplaceWithDollarSign(buf []byte) []byte {
 
 func copySettings(from Session, into Session) {
 	into.SetPreparedStatementCache(from.PreparedStatementCacheEnabled())
+	into.SetPreparedStatementCacheCapacity(from.PreparedStatementCacheCapacity())
+	into.SetPreparedStatementCacheMaxIdleTime(from.PreparedStatementCacheMaxIdleTime())
 	into.SetConnMaxLifetime(from.ConnMaxLifetime())
 	into.SetConnMaxIdleTime(from.ConnMaxIdleTime())
 	into.SetMaxIdleConns(from.MaxIdleConns())
 	into.SetMaxOpenConns(from.MaxOpenConns())
+	into.SetSlowQueryThreshold(from.SlowQueryThreshold())
+	into.SetQueryArgsLogging(from.QueryArgsLoggingEnabled())
+	into.SetQueryStackCapture(from.QueryStackCaptureEnabled())
+	into.SetQueryLogLevel(from.QueryLogLevel())
+	into.SetClock(from.Clock())
+	into.SetStrictMapping(from.StrictMappingEnabled())
+	into.SetRecordTracking(from.RecordTrackingEnabled())
+	into.SetFieldMapping(from.FieldMapping())
 }
 
 func newSessionID() uint64 {
@@ -1101,4 +1323,7 @@ func TxContext(ctx context.Context, sess db.Session, fn func(tx db.Session) erro
 	return fmt.Errorf("db: giving up trying to commit transaction: %w", txErr)
 }
 
-var _ = db.Session(&sessionWithContext{})
+var (
+	_ = db.Session(&sessionWithContext{})
+	_ = db.StatsReporter(&sessionWithContext{})
+)
@@ -3,6 +3,7 @@ package sqladapter
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/upper/db/v4"
@@ -46,3 +47,102 @@ func TestReplaceWithDollarSign(t *testing.T) {
 		})
 	}
 }
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Fatal(v ...interface{})                 { l.Print(v...) }
+func (l *testLogger) Fatalf(format string, v ...interface{}) { l.Printf(format, v...) }
+func (l *testLogger) Print(v ...interface{})                 { l.lines = append(l.lines, fmt.Sprint(v...)) }
+func (l *testLogger) Printf(format string, v ...interface{}) { l.Print(fmt.Sprintf(format, v...)) }
+func (l *testLogger) Panic(v ...interface{})                 { l.Print(v...) }
+func (l *testLogger) Panicf(format string, v ...interface{}) { l.Printf(format, v...) }
+
+func TestQueryLog(t *testing.T) {
+	logger := &testLogger{}
+
+	logLevel := db.LC().Level()
+	db.LC().SetLogger(logger)
+	db.LC().SetLevel(db.LogLevelDebug)
+	defer func() {
+		db.LC().SetLogger(nil)
+		db.LC().SetLevel(logLevel)
+	}()
+
+	now := time.Now()
+
+	t.Run("Defaults", func(t *testing.T) {
+		logger.lines = nil
+		settings := db.NewSettings()
+
+		queryLog(settings, &db.QueryStatus{
+			RawQuery: "SELECT ?",
+			Args:     []interface{}{42},
+			Start:    now,
+			End:      now.Add(time.Millisecond),
+		})
+
+		assert.Len(t, logger.lines, 1)
+		assert.Contains(t, logger.lines[0], "Arguments:")
+		assert.Contains(t, logger.lines[0], "Stack:")
+		assert.NotContains(t, logger.lines[0], db.ErrWarnSlowQuery.Error())
+	})
+
+	t.Run("SlowQueryThreshold", func(t *testing.T) {
+		logger.lines = nil
+		settings := db.NewSettings()
+		settings.SetSlowQueryThreshold(time.Second)
+
+		status := &db.QueryStatus{Start: now, End: now.Add(time.Millisecond * 500)}
+		queryLog(settings, status)
+		assert.NoError(t, status.Err)
+
+		settings.SetSlowQueryThreshold(time.Millisecond * 100)
+
+		status = &db.QueryStatus{Start: now, End: now.Add(time.Millisecond * 500)}
+		queryLog(settings, status)
+		assert.ErrorIs(t, status.Err, db.ErrWarnSlowQuery)
+
+		settings.SetSlowQueryThreshold(0)
+
+		status = &db.QueryStatus{Start: now, End: now.Add(time.Hour)}
+		queryLog(settings, status)
+		assert.NoError(t, status.Err)
+	})
+
+	t.Run("ArgsAndStack", func(t *testing.T) {
+		logger.lines = nil
+		settings := db.NewSettings()
+		settings.SetQueryArgsLogging(false)
+		settings.SetQueryStackCapture(false)
+
+		queryLog(settings, &db.QueryStatus{
+			RawQuery: "SELECT ?",
+			Args:     []interface{}{42},
+			Start:    now,
+			End:      now,
+		})
+
+		assert.Len(t, logger.lines, 1)
+		assert.NotContains(t, logger.lines[0], "Arguments:")
+		assert.NotContains(t, logger.lines[0], "Stack:")
+	})
+
+	t.Run("QueryLogLevel", func(t *testing.T) {
+		logger.lines = nil
+		settings := db.NewSettings()
+		settings.SetQueryLogLevel(db.LogLevelWarn)
+
+		queryLog(settings, &db.QueryStatus{Start: now, End: now})
+		assert.Len(t, logger.lines, 0)
+
+		queryLog(settings, &db.QueryStatus{Err: db.ErrNoMoreRows, Start: now, End: now})
+		assert.Len(t, logger.lines, 1)
+
+		settings.SetQueryLogLevel(db.LogLevelError)
+
+		queryLog(settings, &db.QueryStatus{Err: db.ErrNoMoreRows, Start: now, End: now})
+		assert.Len(t, logger.lines, 1)
+	})
+}
@@ -19,6 +19,10 @@ type Stmt struct {
 	query string
 	mu    sync.Mutex
 
+	// parent is the statement a transaction-specific statement was created
+	// from, it's kept open until this statement is closed.
+	parent *Stmt
+
 	count int64
 	dead  bool
 }
@@ -33,6 +37,14 @@ func NewStatement(stmt *sql.Stmt, query string) *Stmt {
 	return s
 }
 
+// newTxStatement creates an opened statement that is bound to a transaction
+// and keeps the given parent statement in use until it's closed.
+func newTxStatement(stmt *sql.Stmt, parent *Stmt) *Stmt {
+	s := NewStatement(stmt, parent.query)
+	s.parent = parent
+	return s
+}
+
 // Open marks the statement as in-use
 func (c *Stmt) Open() (*Stmt, error) {
 	c.mu.Lock()
@@ -65,6 +77,9 @@ func (c *Stmt) checkClose() error {
 		}
 		// Reduce active statements counter.
 		atomic.AddInt64(&activeStatements, -1)
+		if c.parent != nil {
+			return c.parent.Close()
+		}
 	}
 	return nil
 }
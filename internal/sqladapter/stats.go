@@ -0,0 +1,176 @@
+package sqladapter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter/exql"
+)
+
+var statementTypeNames = map[exql.Type]string{
+	exql.Truncate:     "TRUNCATE",
+	exql.DropTable:    "DROP TABLE",
+	exql.DropDatabase: "DROP DATABASE",
+	exql.Count:        "COUNT",
+	exql.Insert:       "INSERT",
+	exql.Select:       "SELECT",
+	exql.Update:       "UPDATE",
+	exql.Delete:       "DELETE",
+}
+
+type queryStatsKey struct {
+	typ   string
+	table string
+}
+
+// statsRecorder aggregates the metrics of a session and all its clones.
+type statsRecorder struct {
+	mu sync.Mutex
+
+	queries   map[queryStatsKey]*db.QueryStats
+	collector db.StatsCollector
+
+	stmtCacheHits      uint64
+	stmtCacheMisses    uint64
+	stmtCacheEvictions uint64
+}
+
+func newStatsRecorder() *statsRecorder {
+	return &statsRecorder{
+		queries: make(map[queryStatsKey]*db.QueryStats),
+	}
+}
+
+func (r *statsRecorder) setCollector(collector db.StatsCollector) {
+	r.mu.Lock()
+	r.collector = collector
+	r.mu.Unlock()
+}
+
+func (r *statsRecorder) observeQuery(stmt *exql.Statement, status *db.QueryStatus) {
+	metric := db.QueryMetric{
+		Type:     statementType(stmt),
+		Table:    statementTable(stmt),
+		Duration: status.End.Sub(status.Start),
+		Err:      status.Err,
+	}
+
+	r.mu.Lock()
+	key := queryStatsKey{typ: metric.Type, table: metric.Table}
+	stats, ok := r.queries[key]
+	if !ok {
+		stats = &db.QueryStats{
+			Type:    metric.Type,
+			Table:   metric.Table,
+			Latency: *db.NewLatencyHistogram(db.DefaultLatencyBuckets),
+		}
+		r.queries[key] = stats
+	}
+	stats.Count++
+	if metric.Err != nil {
+		stats.Errors++
+	}
+	stats.Latency.Observe(metric.Duration)
+	collector := r.collector
+	r.mu.Unlock()
+
+	if collector != nil {
+		collector.CollectQuery(metric)
+	}
+}
+
+func (r *statsRecorder) observeStatementCache(hit bool) {
+	if hit {
+		atomic.AddUint64(&r.stmtCacheHits, 1)
+	} else {
+		atomic.AddUint64(&r.stmtCacheMisses, 1)
+	}
+
+	r.mu.Lock()
+	collector := r.collector
+	r.mu.Unlock()
+
+	if collector != nil {
+		collector.CollectStatementCache(hit)
+	}
+}
+
+func (r *statsRecorder) observeStatementCacheEviction() {
+	atomic.AddUint64(&r.stmtCacheEvictions, 1)
+
+	r.mu.Lock()
+	collector := r.collector
+	r.mu.Unlock()
+
+	if collector != nil {
+		collector.CollectStatementCacheEviction()
+	}
+}
+
+func (r *statsRecorder) snapshot() db.Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := db.Stats{
+		Queries:                         make([]db.QueryStats, 0, len(r.queries)),
+		PreparedStatementCacheHits:      atomic.LoadUint64(&r.stmtCacheHits),
+		PreparedStatementCacheMisses:    atomic.LoadUint64(&r.stmtCacheMisses),
+		PreparedStatementCacheEvictions: atomic.LoadUint64(&r.stmtCacheEvictions),
+	}
+
+	for _, qs := range r.queries {
+		copied := *qs
+		copied.Latency.Counts = append([]uint64(nil), qs.Latency.Counts...)
+		stats.Queries = append(stats.Queries, copied)
+	}
+
+	sort.Slice(stats.Queries, func(i, j int) bool {
+		if stats.Queries[i].Type == stats.Queries[j].Type {
+			return stats.Queries[i].Table < stats.Queries[j].Table
+		}
+		return stats.Queries[i].Type < stats.Queries[j].Type
+	})
+
+	return stats
+}
+
+// statementType returns the name of the statement type, raw SQL statements are
+// named after their first keyword.
+func statementType(stmt *exql.Statement) string {
+	if name, ok := statementTypeNames[stmt.Type]; ok {
+		return name
+	}
+	if fields := strings.Fields(stmt.SQL); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return "SQL"
+}
+
+// statementTable returns the name of the table (or tables) the statement
+// targets, aliases are discarded.
+func statementTable(stmt *exql.Statement) string {
+	switch t := stmt.Table.(type) {
+	case *exql.Table:
+		return tableName(t.Name)
+	case *exql.Columns:
+		names := make([]string, 0, len(t.Columns))
+		for i := range t.Columns {
+			if column, ok := t.Columns[i].(*exql.Column); ok {
+				names = append(names, tableName(column.Name))
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func tableName(name interface{}) string {
+	if fields := strings.Fields(fmt.Sprintf("%v", name)); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package sqladapter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/sqladapter/exql"
+)
+
+type testStatsCollector struct {
+	queries   []db.QueryMetric
+	hits      int
+	misses    int
+	evictions int
+}
+
+func (c *testStatsCollector) CollectQuery(m db.QueryMetric) {
+	c.queries = append(c.queries, m)
+}
+
+func (c *testStatsCollector) CollectStatementCache(hit bool) {
+	if hit {
+		c.hits++
+		return
+	}
+	c.misses++
+}
+
+func (c *testStatsCollector) CollectStatementCacheEviction() {
+	c.evictions++
+}
+
+func TestStatsRecorder(t *testing.T) {
+	r := newStatsRecorder()
+	collector := &testStatsCollector{}
+	r.setCollector(collector)
+
+	now := time.Now()
+
+	selectStmt := &exql.Statement{
+		Type: exql.Select,
+		Table: exql.JoinColumns(
+			exql.ColumnWithName("artist AS a"),
+			exql.ColumnWithName("publication p"),
+		),
+	}
+	updateStmt := &exql.Statement{
+		Type:  exql.Update,
+		Table: exql.TableWithName("artist"),
+	}
+
+	r.observeQuery(selectStmt, &db.QueryStatus{Start: now, End: now.Add(time.Millisecond * 3)})
+	r.observeQuery(selectStmt, &db.QueryStatus{Start: now, End: now.Add(time.Minute), Err: errors.New("timeout")})
+	r.observeQuery(updateStmt, &db.QueryStatus{Start: now, End: now})
+	r.observeQuery(exql.RawSQL(" delete from artist"), &db.QueryStatus{Start: now, End: now})
+
+	r.observeStatementCache(false)
+	r.observeStatementCache(true)
+	r.observeStatementCache(true)
+	r.observeStatementCacheEviction()
+
+	stats := r.snapshot()
+
+	assert.Equal(t, uint64(2), stats.PreparedStatementCacheHits)
+	assert.Equal(t, uint64(1), stats.PreparedStatementCacheMisses)
+	assert.Equal(t, uint64(1), stats.PreparedStatementCacheEvictions)
+
+	if assert.Len(t, stats.Queries, 3) {
+		assert.Equal(t, "DELETE", stats.Queries[0].Type)
+		assert.Equal(t, "", stats.Queries[0].Table)
+
+		assert.Equal(t, "SELECT", stats.Queries[1].Type)
+		assert.Equal(t, "artist, publication", stats.Queries[1].Table)
+		assert.Equal(t, uint64(2), stats.Queries[1].Count)
+		assert.Equal(t, uint64(1), stats.Queries[1].Errors)
+		assert.Equal(t, uint64(2), stats.Queries[1].Latency.Count)
+		assert.Equal(t, uint64(1), stats.Queries[1].Latency.Counts[1])
+		assert.Equal(t, uint64(1), stats.Queries[1].Latency.Counts[len(db.DefaultLatencyBuckets)])
+
+		assert.Equal(t, "UPDATE", stats.Queries[2].Type)
+		assert.Equal(t, "artist", stats.Queries[2].Table)
+	}
+
+	assert.Len(t, collector.queries, 4)
+	assert.Equal(t, 2, collector.hits)
+	assert.Equal(t, 1, collector.misses)
+	assert.Equal(t, 1, collector.evictions)
+
+	// Snapshots must not share state with the recorder.
+	stats.Queries[1].Latency.Counts[1] = 100
+	assert.Equal(t, uint64(1), r.snapshot().Queries[1].Latency.Counts[1])
+}
@@ -0,0 +1,101 @@
+package sqladapter
+
+import (
+	"reflect"
+	"time"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// sessionNow returns the current time according to the clock of the given
+// session.
+func sessionNow(sess db.Session) time.Time {
+	if s, ok := sess.(Session); ok {
+		return s.Now()
+	}
+	return sess.Clock()()
+}
+
+// setAutoTimestamps sets the fields of item that are tagged with the
+// autoupdatetime option to now. When creating, fields tagged with the
+// autocreatetime option are set to now as well unless they already have a
+// value.
+func setAutoTimestamps(mapper *reflectx.Mapper, item interface{}, now time.Time, creating bool) {
+	itemV := reflect.ValueOf(item)
+	if itemV.Kind() != reflect.Ptr || itemV.IsNil() {
+		return
+	}
+	itemV = reflect.Indirect(itemV)
+	if itemV.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, fi := range mapper.TypeMap(itemV.Type()).Index {
+		if _, ok := fi.Options["autoupdatetime"]; ok {
+			setTimeField(itemV, fi.Index, now, false)
+			continue
+		}
+		if _, ok := fi.Options["autocreatetime"]; ok && creating {
+			setTimeField(itemV, fi.Index, now, true)
+		}
+	}
+}
+
+// withAutoUpdateTimestamps returns the given update values with the fields
+// tagged with the autoupdatetime option set to now. Pointers to structs are
+// modified in place while struct values are copied, other values are
+// returned as they are.
+func withAutoUpdateTimestamps(mapper *reflectx.Mapper, values interface{}, now time.Time) interface{} {
+	valuesV := reflect.ValueOf(values)
+	if !valuesV.IsValid() || !hasAutoUpdateTimestamps(mapper, reflectx.Deref(valuesV.Type())) {
+		return values
+	}
+	if valuesV.Kind() != reflect.Ptr {
+		copied := reflect.New(valuesV.Type())
+		copied.Elem().Set(valuesV)
+		values = copied.Interface()
+	}
+	setAutoTimestamps(mapper, values, now, false)
+	return values
+}
+
+// hasAutoUpdateTimestamps returns true if the given struct type has fields
+// tagged with the autoupdatetime option.
+func hasAutoUpdateTimestamps(mapper *reflectx.Mapper, t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for _, fi := range mapper.TypeMap(t).Index {
+		if _, ok := fi.Options["autoupdatetime"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setColumnTime sets the field of item that is mapped to column to now.
+func setColumnTime(mapper *reflectx.Mapper, item interface{}, column string, now time.Time) {
+	itemV := reflect.Indirect(reflect.ValueOf(item))
+	if itemV.Kind() != reflect.Struct {
+		return
+	}
+	if fi, ok := mapper.TypeMap(itemV.Type()).Names[column]; ok {
+		setTimeField(itemV, fi.Index, now, false)
+	}
+}
+
+func setTimeField(itemV reflect.Value, index []int, now time.Time, onlyIfZero bool) {
+	field := reflect.Indirect(reflectx.FieldByIndexes(itemV, index))
+	if !field.CanSet() || (onlyIfZero && !field.IsZero()) {
+		return
+	}
+
+	value := reflect.ValueOf(now)
+	switch {
+	case value.Type().AssignableTo(field.Type()):
+		field.Set(value)
+	case value.Type().ConvertibleTo(field.Type()):
+		field.Set(value.Convert(field.Type()))
+	}
+}
@@ -0,0 +1,55 @@
+package sqladapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+type timestampedItem struct {
+	ID        uint64     `db:"id"`
+	CreatedAt time.Time  `db:"created_at,autocreatetime"`
+	UpdatedAt *time.Time `db:"updated_at,autoupdatetime,omitempty"`
+}
+
+func TestSetAutoTimestamps(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	item := timestampedItem{}
+
+	setAutoTimestamps(sqlbuilder.Mapper, &item, now, true)
+	assert.Equal(t, now, item.CreatedAt)
+	if assert.NotNil(t, item.UpdatedAt) {
+		assert.Equal(t, now, *item.UpdatedAt)
+	}
+
+	setAutoTimestamps(sqlbuilder.Mapper, &item, later, true)
+	assert.Equal(t, now, item.CreatedAt, "existing creation times are kept")
+	assert.Equal(t, later, *item.UpdatedAt)
+
+	item = timestampedItem{}
+	setAutoTimestamps(sqlbuilder.Mapper, &item, now, false)
+	assert.True(t, item.CreatedAt.IsZero())
+	assert.Equal(t, now, *item.UpdatedAt)
+}
+
+func TestWithAutoUpdateTimestamps(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	item := timestampedItem{ID: 1}
+	values := withAutoUpdateTimestamps(sqlbuilder.Mapper, item, now)
+	if assert.IsType(t, &timestampedItem{}, values) {
+		assert.Equal(t, now, *values.(*timestampedItem).UpdatedAt)
+	}
+	assert.Nil(t, item.UpdatedAt, "struct values are copied")
+
+	values = withAutoUpdateTimestamps(sqlbuilder.Mapper, &item, now)
+	assert.Equal(t, &item, values)
+	assert.Equal(t, now, *item.UpdatedAt)
+
+	m := map[string]interface{}{"id": 1}
+	assert.Equal(t, m, withAutoUpdateTimestamps(sqlbuilder.Mapper, m, now))
+}
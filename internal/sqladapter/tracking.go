@@ -0,0 +1,199 @@
+package sqladapter
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+// recordTracker is implemented by sessions that keep snapshots of the records
+// they fetch in order to find out which columns were changed.
+type recordTracker interface {
+	trackRecords(dst interface{})
+	trackRecord(record db.Record)
+	recordChanges(record db.Record) ([]string, bool)
+}
+
+// recordSnapshot is the snapshot a record had before it was tracked within a
+// transaction.
+type recordSnapshot struct {
+	record db.TrackedRecord
+	values map[string]interface{}
+}
+
+// trackRecords takes a snapshot of the records held by dst, which can be a
+// pointer to a record or a pointer to a slice of records.
+func (sess *sessionWithContext) trackRecords(dst interface{}) {
+	if !sess.RecordTrackingEnabled() {
+		return
+	}
+	_ = eachRecord(dst, func(record db.Record) error {
+		sess.trackRecord(record)
+		return nil
+	})
+}
+
+func (sess *sessionWithContext) trackRecord(record db.Record) {
+	tracked, ok := record.(db.TrackedRecord)
+	if !ok || !sess.RecordTrackingEnabled() || reflect.TypeOf(record).Kind() != reflect.Ptr {
+		return
+	}
+	values, err := snapshotValues(sess.FieldMapper(), record)
+	if err != nil {
+		return
+	}
+
+	if sess.IsTransaction() {
+		sess.trackedRecordsMu.Lock()
+		sess.trackedRecords = append(sess.trackedRecords, recordSnapshot{record: tracked, values: tracked.Snapshot()})
+		sess.trackedRecordsMu.Unlock()
+	}
+	tracked.SetSnapshot(values)
+}
+
+// recordChanges returns the columns of the record that changed since its
+// snapshot was taken, false is returned if there is no snapshot.
+func (sess *sessionWithContext) recordChanges(record db.Record) ([]string, bool) {
+	tracked, ok := record.(db.TrackedRecord)
+	if !ok || !sess.RecordTrackingEnabled() || reflect.TypeOf(record).Kind() != reflect.Ptr {
+		return nil, false
+	}
+
+	snapshot := tracked.Snapshot()
+	if snapshot == nil {
+		return nil, false
+	}
+
+	values, err := snapshotValues(sess.FieldMapper(), record)
+	if err != nil {
+		return nil, false
+	}
+
+	changes := []string{}
+	for column, value := range values {
+		previous, ok := snapshot[column]
+		if !ok || !reflect.DeepEqual(previous, value) {
+			changes = append(changes, column)
+		}
+	}
+	sort.Strings(changes)
+
+	return changes, true
+}
+
+// commitTrackedRecords keeps the snapshots taken within a transaction.
+func (sess *sessionWithContext) commitTrackedRecords() {
+	sess.trackedRecordsMu.Lock()
+	defer sess.trackedRecordsMu.Unlock()
+
+	sess.trackedRecords = nil
+}
+
+// rollbackTrackedRecords gives the records tracked within a transaction the
+// snapshots they had before, since the values they were saved with were not
+// written.
+func (sess *sessionWithContext) rollbackTrackedRecords() {
+	sess.trackedRecordsMu.Lock()
+	defer sess.trackedRecordsMu.Unlock()
+
+	for i := len(sess.trackedRecords) - 1; i >= 0; i-- {
+		sess.trackedRecords[i].record.SetSnapshot(sess.trackedRecords[i].values)
+	}
+	sess.trackedRecords = nil
+}
+
+func (sess *sessionWithContext) Changed(record db.Record) []string {
+	if changes, ok := sess.recordChanges(record); ok {
+		return changes
+	}
+	if record == nil {
+		return nil
+	}
+
+	columns, _, err := sqlbuilder.Map(record, &sqlbuilder.MapOptions{IncludeZeroed: true, IncludeNil: true, Mapper: sess.FieldMapper()})
+	if err != nil {
+		return nil
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// snapshotValues returns a copy of the values of every column of item.
+func snapshotValues(mapper *reflectx.Mapper, item interface{}) (map[string]interface{}, error) {
+	columns, values, err := sqlbuilder.Map(item, &sqlbuilder.MapOptions{IncludeZeroed: true, IncludeNil: true, SkipCodecs: true, Mapper: mapper})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]interface{}, len(columns))
+	for i := range columns {
+		snapshot[columns[i]] = snapshotValue(values[i])
+	}
+	return snapshot, nil
+}
+
+// snapshotValue copies the data pointers, slices and maps refer to, so changes
+// made in place are detected.
+func snapshotValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	switch {
+	case v.Kind() == reflect.Slice && !v.IsNil():
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		return copied.Interface()
+	case v.Kind() == reflect.Map && !v.IsNil():
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return copied.Interface()
+	}
+	return v.Interface()
+}
+
+// recordUpdateColumns returns the columns Save needs to update. A nil slice
+// means every column, false means the record has no changes and does not
+// need to be updated.
+func recordUpdateColumns(sess db.Session, store db.Store, record db.Record) ([]string, bool) {
+	if _, ok := store.(db.StoreUpdater); ok {
+		return nil, true
+	}
+	tracker, ok := sess.(recordTracker)
+	if !ok {
+		return nil, true
+	}
+	changes, ok := tracker.recordChanges(record)
+	if !ok {
+		return nil, true
+	}
+	if len(changes) == 0 {
+		return nil, false
+	}
+
+	for _, fi := range sqlbuilder.MapperOf(sess).TypeMap(reflect.TypeOf(record).Elem()).Index {
+		if _, ok := fi.Options["autoupdatetime"]; ok && !slices.Contains(changes, fi.Name) {
+			changes = append(changes, fi.Name)
+		}
+	}
+	return changes, true
+}
+
+func trackRecord(sess db.Session, record db.Record) {
+	if tracker, ok := sess.(recordTracker); ok {
+		tracker.trackRecord(record)
+	}
+}
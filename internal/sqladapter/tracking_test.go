@@ -0,0 +1,37 @@
+package sqladapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4/internal/sqlbuilder"
+)
+
+type trackedItem struct {
+	ID        uint64            `db:"id"`
+	Name      string            `db:"name"`
+	Tags      []string          `db:"tags"`
+	Attrs     map[string]string `db:"attrs"`
+	DeletedAt *time.Time        `db:"deleted_at,omitempty"`
+}
+
+func TestSnapshotValues(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	deletedAt := now
+
+	item := trackedItem{ID: 1, Name: "foo", Tags: []string{"a", "b"}, Attrs: map[string]string{"a": "b"}, DeletedAt: &deletedAt}
+
+	snapshot, err := snapshotValues(sqlbuilder.Mapper, &item)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", snapshot["name"])
+	assert.Equal(t, now, snapshot["deleted_at"])
+
+	// Changes made in place do not affect the snapshot.
+	item.Tags[0] = "c"
+	item.Attrs["a"] = "c"
+	*item.DeletedAt = now.Add(time.Hour)
+	assert.Equal(t, []string{"a", "b"}, snapshot["tags"])
+	assert.Equal(t, map[string]string{"a": "b"}, snapshot["attrs"])
+	assert.Equal(t, now, snapshot["deleted_at"])
+}
@@ -43,6 +43,13 @@ import (
 type MapOptions struct {
 	IncludeZeroed bool
 	IncludeNil    bool
+
+	// SkipCodecs leaves the values of fields with a codec tag option as they
+	// are instead of encoding them.
+	SkipCodecs bool
+
+	// Mapper maps the fields of structs to columns, Mapper is used if nil.
+	Mapper *reflectx.Mapper
 }
 
 var defaultMapOptions = MapOptions{
@@ -67,6 +74,10 @@ type iterator struct {
 	sess   exprDB
 	cursor *sql.Rows // This is the main query cursor. It starts as a nil value.
 	err    error
+
+	// strict overrides the strict mapping setting of the session if it's not
+	// nil.
+	strict *bool
 }
 
 type fieldValue struct {
@@ -91,6 +102,18 @@ type exprDB interface {
 	Context() context.Context
 }
 
+// exprErrorConverter is implemented by sessions that translate driver errors
+// into portable ones.
+type exprErrorConverter interface {
+	Err(errIn error) (errOut error)
+}
+
+// exprExplainer is implemented by sessions that can describe the execution
+// plan of a statement.
+type exprExplainer interface {
+	StatementExplain(ctx context.Context, stmt *exql.Statement, opts *db.ExplainOptions, args ...interface{}) (*db.QueryPlan, error)
+}
+
 type sqlBuilder struct {
 	sess exprDB
 	t    *templateWithUtils
@@ -115,7 +138,7 @@ func WithTemplate(t *exql.Template) db.SQL {
 }
 
 func (b *sqlBuilder) NewIteratorContext(ctx context.Context, rows *sql.Rows) db.Iterator {
-	return &iterator{b.sess, rows, nil}
+	return &iterator{b.sess, rows, nil, nil}
 }
 
 func (b *sqlBuilder) NewIterator(rows *sql.Rows) db.Iterator {
@@ -128,7 +151,7 @@ func (b *sqlBuilder) Iterator(query interface{}, args ...interface{}) db.Iterato
 
 func (b *sqlBuilder) IteratorContext(ctx context.Context, query interface{}, args ...interface{}) db.Iterator {
 	rows, err := b.QueryContext(ctx, query, args...)
-	return &iterator{b.sess, rows, err}
+	return &iterator{b.sess, rows, err, nil}
 }
 
 func (b *sqlBuilder) Prepare(query interface{}) (*sql.Stmt, error) {
@@ -258,7 +281,11 @@ func Map(item interface{}, options *MapOptions) ([]string, []interface{}, error)
 
 	switch itemT.Kind() {
 	case reflect.Struct:
-		fieldMap := Mapper.TypeMap(itemT).Names
+		mapper := options.Mapper
+		if mapper == nil {
+			mapper = Mapper
+		}
+		fieldMap := mapper.TypeMap(itemT).Names
 		nfields := len(fieldMap)
 
 		fv.values = make([]interface{}, 0, nfields)
@@ -308,7 +335,7 @@ func Map(item interface{}, options *MapOptions) ([]string, []interface{}, error)
 			}
 
 			fv.fields = append(fv.fields, fi.Name)
-			v, err := marshal(value)
+			v, err := marshalField(fi, value, options)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -344,6 +371,58 @@ func Map(item interface{}, options *MapOptions) ([]string, []interface{}, error)
 	return fv.fields, fv.values, nil
 }
 
+// MapColumns maps the given columns of a pointer to map or struct to their
+// values. Unlike Map, zero and nil values of fields tagged with omitempty are
+// kept as they are, so they can be written. Columns the item has no value for
+// are skipped.
+func MapColumns(item interface{}, columns []string, options *MapOptions) (map[string]interface{}, error) {
+	if options == nil {
+		options = &defaultMapOptions
+	}
+
+	itemV := reflect.Indirect(reflect.ValueOf(item))
+	if itemV.Kind() != reflect.Struct {
+		names, values, err := Map(item, options)
+		if err != nil {
+			return nil, err
+		}
+		set := make(map[string]interface{}, len(columns))
+		for i := range names {
+			for _, column := range columns {
+				if names[i] == column {
+					set[column] = values[i]
+				}
+			}
+		}
+		return set, nil
+	}
+
+	mapper := options.Mapper
+	if mapper == nil {
+		mapper = Mapper
+	}
+	fieldMap := mapper.TypeMap(itemV.Type()).Names
+
+	set := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		fi, ok := fieldMap[column]
+		if !ok {
+			continue
+		}
+		fld := reflectx.FieldByIndexesReadOnly(itemV, fi.Index)
+		if fld.Kind() == reflect.Ptr && fld.IsNil() {
+			set[column] = nil
+			continue
+		}
+		v, err := marshalField(fi, fld.Interface(), options)
+		if err != nil {
+			return nil, err
+		}
+		set[column] = v
+	}
+	return set, nil
+}
+
 func columnFragments(columns []interface{}) ([]exql.Fragment, []interface{}, error) {
 	f := make([]exql.Fragment, len(columns))
 	args := []interface{}{}
@@ -392,6 +471,10 @@ func columnFragments(columns []interface{}) ([]exql.Fragment, []interface{}, err
 		case fmt.Stringer:
 			f[i] = exql.ColumnWithName(v.String())
 		default:
+			if t := reflect.TypeOf(v); t != nil && reflectx.Deref(t).Kind() == reflect.Struct {
+				f[i] = &structColumns{t: reflectx.Deref(t)}
+				continue
+			}
 			var err error
 			f[i], err = exql.NewRawValue(columns[i])
 			if err != nil {
@@ -465,6 +548,9 @@ func (iter *iterator) Scan(dst ...interface{}) error {
 }
 
 func (iter *iterator) setErr(err error) error {
+	if converter, ok := iter.sess.(exprErrorConverter); ok && err != nil {
+		err = converter.Err(err)
+	}
 	iter.err = err
 	return iter.err
 }
@@ -556,6 +642,24 @@ func marshal(v interface{}) (interface{}, error) {
 	return v, nil
 }
 
+// marshalField returns the value of a field as it is stored, using the codec
+// given by the field's tag options, if any.
+func marshalField(fi *reflectx.FieldInfo, value interface{}, options *MapOptions) (interface{}, error) {
+	name, hasCodec := fi.Options["codec"]
+	if !hasCodec || options.SkipCodecs {
+		return marshal(value)
+	}
+	codec, err := lookupCodec(name)
+	if err != nil {
+		return nil, err
+	}
+	v, err := codec.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %q: %w", fi.Name, err)
+	}
+	return v, nil
+}
+
 func (fv *fieldValue) Len() int {
 	return len(fv.fields)
 }
@@ -1,10 +1,14 @@
 package sqlbuilder
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/upper/db/v4"
@@ -656,6 +660,28 @@ func TestSelect(t *testing.T) {
 		b.SelectFrom("artist").CrossJoin("publication").String(),
 	)
 
+	{
+		type artist struct {
+			ID   int    `db:"id"`
+			Name string `db:"name"`
+		}
+		type publication struct {
+			ID     int     `db:"id"`
+			Title  string  `db:"title"`
+			Artist *artist `db:"artist,prefix=a."`
+		}
+
+		assert.Equal(
+			`SELECT "p"."id" AS "id", "p"."title" AS "title", "a"."id" AS "a.id", "a"."name" AS "a.name" FROM "publication" AS "p" LEFT JOIN "artist" AS "a" ON (a.id = p.author_id)`,
+			b.Select(publication{}).From("publication p").LeftJoin("artist a").On("a.id = p.author_id").String(),
+		)
+
+		assert.Equal(
+			`SELECT "id", "name"`,
+			b.Select(&artist{}).String(),
+		)
+	}
+
 	assert.Equal(
 		`SELECT * FROM "artist" JOIN "publication" USING ("id")`,
 		b.SelectFrom("artist").Join("publication").Using("id").String(),
@@ -1457,6 +1483,86 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestMapCodecs(t *testing.T) {
+	type item struct {
+		Name  string   `db:"name"`
+		Tags  []string `db:"tags,codec=json"`
+		Notes *string  `db:"notes,codec=json"`
+	}
+
+	columns, values, err := Map(item{Name: "foo", Tags: []string{"a", "b"}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "notes", "tags"}, columns)
+	assert.Equal(t, []interface{}{"foo", nil, `["a","b"]`}, values)
+
+	_, values, err = Map(item{Tags: []string{"a"}}, &MapOptions{SkipCodecs: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, values[2])
+
+	_, _, err = Map(struct {
+		Name string `db:"name,codec=unknown"`
+	}{"foo"}, nil)
+	assert.ErrorIs(t, err, db.ErrUnknownCodec)
+}
+
+func TestMapColumns(t *testing.T) {
+	type item struct {
+		ID    int64    `db:"id,omitempty"`
+		Name  string   `db:"name,omitempty"`
+		Tags  []string `db:"tags,codec=json"`
+		Notes *string  `db:"notes,omitempty"`
+	}
+
+	values, err := MapColumns(&item{ID: 1, Tags: []string{"a"}}, []string{"name", "notes", "tags", "unknown"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "", "notes": nil, "tags": `["a"]`}, values)
+
+	values, err = MapColumns(map[string]interface{}{"id": 1, "name": "foo"}, []string{"name"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "foo"}, values)
+}
+
+func TestMapFieldMapping(t *testing.T) {
+	type item struct {
+		ID        int64     `db:"id,omitempty"`
+		FirstName string    `db:",omitempty"`
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time
+		Nickname  sql.NullString
+	}
+
+	now := time.Now()
+	value := item{FirstName: "Joe", CreatedAt: now, UpdatedAt: now}
+
+	columns, _, err := Map(value, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"created_at"}, columns)
+
+	mapper := NewMapper(db.FieldMapping{NameFunc: db.SnakeCase})
+	columns, values, err := Map(value, &MapOptions{Mapper: mapper})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"created_at", "first_name", "nickname", "updated_at"}, columns)
+	assert.Equal(t, "Joe", values[1])
+
+	assert.Same(t, Mapper, NewMapper(db.FieldMapping{}))
+	assert.Same(t, Mapper, MapperOf(nil))
+
+	// Mappers are shared by the same field mapping.
+	assert.Same(t, mapper, NewMapper(db.FieldMapping{TagName: "db", NameFunc: db.SnakeCase}))
+	assert.NotSame(t, mapper, NewMapper(db.FieldMapping{NameFunc: db.CamelCase}))
+	assert.NotSame(t, mapper, NewMapper(db.FieldMapping{NameFunc: db.SnakeCase, CaseInsensitive: true}))
+
+	prefixed := func(prefix string) func(string) string {
+		return func(name string) string { return prefix + db.SnakeCase(name) }
+	}
+	assert.NotSame(t, NewMapper(db.FieldMapping{NameFunc: prefixed("a_")}), NewMapper(db.FieldMapping{NameFunc: prefixed("b_")}))
+
+	folded := NewMapper(db.FieldMapping{NameFunc: db.CamelCase, CaseInsensitive: true})
+	fi, ok := folded.TypeMap(reflect.TypeOf(item{})).Lookup("FIRSTNAME")
+	assert.True(t, ok)
+	assert.Equal(t, "firstName", fi.Name)
+}
+
 func TestDelete(t *testing.T) {
 	bt := WithTemplate(&testTemplate)
 	assert := assert.New(t)
@@ -1560,6 +1666,235 @@ func TestPaginate(t *testing.T) {
 	}
 }
 
+func TestIsScalar(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, v := range []interface{}{int64(0), "", time.Time{}, sql.NullString{}, []byte{}, new(int)} {
+		assert.True(isScalar(reflect.TypeOf(v)), "%T", v)
+	}
+	for _, v := range []interface{}{struct{}{}, &struct{}{}, map[string]interface{}{}, []interface{}{}} {
+		assert.False(isScalar(reflect.TypeOf(v)), "%T", v)
+	}
+}
+
+func TestCheckMapping(t *testing.T) {
+	type address struct {
+		Street string `db:"street"`
+		City   string `db:"city,optional"`
+	}
+	type person struct {
+		ID       int64     `db:"id"`
+		Name     string    `db:"name"`
+		Nickname string    `db:"nickname,optional"`
+		Address  *address  `db:"address,prefix=a."`
+		Created  time.Time `db:"created_at"`
+	}
+
+	strict, lax := true, false
+
+	assert.NoError(t, checkMapping(&iterator{strict: &lax}, reflect.TypeOf(person{}), []string{"id"}))
+	assert.NoError(t, checkMapping(&iterator{strict: &strict}, reflect.TypeOf(map[string]interface{}{}), []string{"id"}))
+	assert.NoError(t, checkMapping(&iterator{strict: &strict}, reflect.TypeOf(int64(0)), []string{"id"}))
+	assert.NoError(t, checkMapping(&iterator{strict: &strict}, reflect.TypeOf(&person{}), []string{"id", "name", "a.street", "created_at"}))
+
+	err := checkMapping(&iterator{strict: &strict}, reflect.TypeOf(person{}), []string{"id", "name", "age", "a.city"})
+	var mappingErr *db.MappingError
+	if assert.ErrorAs(t, err, &mappingErr) {
+		assert.Equal(t, []string{"age"}, mappingErr.UnmappedColumns)
+		assert.Equal(t, []string{"created_at", "a.street"}, mappingErr.MissingFields)
+		assert.Equal(t, `upper: can't map result set into sqlbuilder.person: unmapped columns "age"; missing fields "created_at", "a.street"`, err.Error())
+	}
+}
+
+func TestChunkKey(t *testing.T) {
+	type artist struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	key, err := chunkKey(Mapper, &[]artist{{ID: 1}, {ID: 2}}, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), key)
+
+	key, err = chunkKey(Mapper, &[]*artist{{ID: 3}}, "a.id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), key)
+
+	key, err = chunkKey(Mapper, &[]map[string]interface{}{{"id": 4}}, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, key)
+
+	_, err = chunkKey(Mapper, &[]artist{{ID: 1}}, "artist_id")
+	assert.Error(t, err)
+
+	_, err = chunkKey(Mapper, &[]int64{1}, "id")
+	assert.Error(t, err)
+}
+
+func TestChunk(t *testing.T) {
+	b := WithTemplate(&testTemplate)
+
+	err := b.SelectFrom("artist").Chunk(nil, 0, func(db.Session, interface{}) error {
+		return nil
+	}, nil)
+	assert.ErrorIs(t, err, db.ErrInvalidChunkSize)
+
+	sel := b.Select("id", "name").From("artist").Where("id > ?", 1).OrderBy("name")
+	bound := sel.(*selector).bind(WithTemplate(&testTemplate).(*sqlBuilder))
+	assert.Equal(t, sel.String(), bound.String())
+	assert.Equal(t, sel.Arguments(), bound.Arguments())
+}
+
+func TestUpdateExpressions(t *testing.T) {
+	b := &sqlBuilder{t: newTemplateWithUtils(&testTemplate)}
+
+	{
+		q := b.Update("post").Set(map[string]interface{}{
+			"views": db.Raw("? + ?", db.Column("views"), 1),
+		}).Where(db.Cond{"id": 3})
+		assert.Equal(t, `UPDATE "post" SET "views" = "views" + $1 WHERE ("id" = $2)`, q.String())
+		assert.Equal(t, []interface{}{1, 3}, q.Arguments())
+	}
+
+	{
+		q := b.Update("post").Set(map[string]interface{}{
+			"synced_at": db.Column("updated_at"),
+		})
+		assert.Equal(t, `UPDATE "post" SET "synced_at" = "updated_at"`, q.String())
+		assert.Empty(t, q.Arguments())
+	}
+
+	{
+		q := b.Update("post").Set(map[string]interface{}{
+			"title": db.Func("CONCAT", db.Column("title"), "!"),
+		})
+		assert.Equal(t, `UPDATE "post" SET "title" = CONCAT("title", $1)`, q.String())
+		assert.Equal(t, []interface{}{"!"}, q.Arguments())
+	}
+
+	{
+		q := b.Update("post").Set(map[string]interface{}{
+			"score": db.Raw("? * ?", 2, db.Column("score")),
+		})
+		assert.Equal(t, `UPDATE "post" SET "score" = $1 * "score"`, q.String())
+		assert.Equal(t, []interface{}{2}, q.Arguments())
+	}
+
+	{
+		// Quoted question marks and the ?? escape are not placeholders.
+		q := b.Update("post").Set(map[string]interface{}{
+			"title": db.Raw(`CASE WHEN ? = '?' OR tags ?? 'a' OR "?" = ? THEN ? END`, db.Column("title"), 1, db.Column("name")),
+		})
+		s, err := q.(*updater).Compile()
+		assert.NoError(t, err)
+		assert.Contains(t, s, `SET "title" = CASE WHEN "title" = '?' OR tags ?? 'a' OR "?" = ? THEN "name" END`)
+		assert.Equal(t, []interface{}{1}, q.Arguments())
+	}
+}
+
+func TestSelectColumnExpressions(t *testing.T) {
+	b := &sqlBuilder{t: newTemplateWithUtils(&testTemplate)}
+
+	q := b.Select(db.Raw("SUM(?) AS _t", db.Column("post.views"))).From("post").Where(db.Cond{"id >": 3})
+	assert.Equal(t, `SELECT SUM("post"."views") AS _t FROM "post" WHERE ("id" > $1)`, q.String())
+	assert.Equal(t, []interface{}{3}, q.Arguments())
+
+	q = b.Select("id", db.Raw("? * ? AS score", db.Column("views"), 2)).From("post")
+	assert.Equal(t, `SELECT "id", "views" * $1 AS score FROM "post"`, q.String())
+	assert.Equal(t, []interface{}{2}, q.Arguments())
+}
+
+func TestCopySource(t *testing.T) {
+	type artist struct {
+		ID   int64   `db:"id,omitempty"`
+		Name string  `db:"name"`
+		Bio  *string `db:"bio,omitempty"`
+	}
+
+	readAll := func(src *CopySource) [][]interface{} {
+		rows := [][]interface{}{}
+		for src.Next() {
+			values, err := src.Values()
+			assert.NoError(t, err)
+			rows = append(rows, values)
+		}
+		assert.NoError(t, src.Err())
+		return rows
+	}
+
+	{
+		bio := "Singer"
+		src, err := NewCopySource(context.Background(), Mapper, []artist{{Name: "Ozzie", Bio: &bio}, {Name: "Flea"}}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bio", "name"}, src.Columns())
+		assert.Equal(t, [][]interface{}{{&bio, "Ozzie"}, {nil, "Flea"}}, readAll(src))
+	}
+
+	{
+		// The ID of the second row is not among the columns of the first one.
+		src, err := NewCopySource(context.Background(), Mapper, []artist{{Name: "Ozzie"}, {ID: 2, Name: "Flea"}}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name"}, src.Columns())
+		assert.True(t, src.Next())
+		_, err = src.Values()
+		assert.NoError(t, err)
+		assert.True(t, src.Next())
+		_, err = src.Values()
+		assert.Error(t, err)
+
+		// Explicit columns pick the values to copy.
+		src, err = NewCopySource(context.Background(), Mapper, []artist{{Name: "Ozzie"}, {ID: 2, Name: "Flea"}}, []string{"name"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]interface{}{{"Ozzie"}, {"Flea"}}, readAll(src))
+	}
+
+	{
+		rows := make(chan map[string]interface{}, 2)
+		rows <- map[string]interface{}{"name": "Ozzie", "id": 1}
+		rows <- map[string]interface{}{"name": "Flea"}
+		close(rows)
+
+		src, err := NewCopySource(context.Background(), Mapper, rows, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, src.Columns())
+		assert.Equal(t, [][]interface{}{{1, "Ozzie"}, {nil, "Flea"}}, readAll(src))
+	}
+
+	{
+		src, err := NewCopySource(context.Background(), Mapper, &[][]interface{}{{"Ozzie"}, {"Flea", 2}}, []string{"name"})
+		assert.NoError(t, err)
+		assert.True(t, src.Next())
+		values, err := src.Values()
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"Ozzie"}, values)
+		assert.True(t, src.Next())
+		_, err = src.Values()
+		assert.Error(t, err)
+	}
+
+	{
+		src, err := NewCopySource(context.Background(), Mapper, []artist{}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, src.Columns())
+		assert.False(t, src.Next())
+
+		_, err = NewCopySource(context.Background(), Mapper, [][]interface{}{{"Ozzie"}}, nil)
+		assert.Error(t, err)
+
+		_, err = NewCopySource(context.Background(), Mapper, artist{}, nil)
+		assert.Error(t, err)
+	}
+
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		src, err := NewCopySource(ctx, Mapper, make(chan artist), []string{"name"})
+		assert.NoError(t, err)
+		cancel()
+		assert.False(t, src.Next())
+		assert.ErrorIs(t, src.Err(), context.Canceled)
+	}
+}
+
 func BenchmarkDelete1(b *testing.B) {
 	bt := WithTemplate(&testTemplate)
 	for n := 0; n < b.N; n++ {
@@ -0,0 +1,169 @@
+package sqlbuilder
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+	"github.com/upper/db/v4/internal/sqladapter/exql"
+)
+
+func (sel *selector) Chunk(ctx context.Context, size uint, fn db.ChunkFunc, opts *db.ChunkOptions) error {
+	if size == 0 {
+		return db.ErrInvalidChunkSize
+	}
+	if opts == nil {
+		opts = &db.ChunkOptions{}
+	}
+	if ctx == nil {
+		ctx = sel.SQL().sess.Context()
+	}
+
+	batch := opts.Into
+	if batch == nil {
+		batch = &[]map[string]interface{}{}
+	}
+
+	sq, err := sel.build()
+	if err != nil {
+		return err
+	}
+	if sq.offset > 0 || (opts.Key != "" && isOrdered(sq)) {
+		return db.ErrInvalidChunkQuery
+	}
+
+	// The limit of the selector caps the number of items that are processed.
+	remaining := int(sq.limit)
+
+	sess, _ := sel.SQL().sess.(db.Session)
+
+	var lastKey interface{}
+	for page := uint(1); ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunkSize := int(size)
+		if remaining > 0 {
+			chunkSize = min(chunkSize, remaining)
+		}
+
+		// The key is only moved forward once the chunk was processed, so a
+		// transaction that is retried processes the same chunk again.
+		n := 0
+		var nextKey interface{}
+		step := func(sess db.Session, sel *selector) error {
+			var iter db.Iterator
+			if opts.Key != "" {
+				q := sel.OrderBy(opts.Key).Limit(chunkSize)
+				if page > 1 {
+					q = q.And(db.Cond{opts.Key + " >": lastKey})
+				}
+				iter = q.IteratorContext(ctx)
+			} else {
+				iter = sel.Paginate(size).Page(page).IteratorContext(ctx)
+			}
+			if err := iter.All(batch); err != nil {
+				return err
+			}
+
+			items := reflect.ValueOf(batch).Elem()
+			if items.Len() > chunkSize {
+				items.SetLen(chunkSize)
+			}
+			if n = items.Len(); n == 0 {
+				return nil
+			}
+			if opts.Key != "" {
+				var err error
+				if nextKey, err = chunkKey(MapperOf(sel.SQL().sess), batch, opts.Key); err != nil {
+					return err
+				}
+			}
+			return fn(sess, batch)
+		}
+
+		if opts.Transaction {
+			if sess == nil {
+				return db.ErrNotSupportedByAdapter
+			}
+			err = sess.TxContext(ctx, func(tx db.Session) error {
+				b, ok := tx.SQL().(*sqlBuilder)
+				if !ok {
+					return db.ErrNotSupportedByAdapter
+				}
+				return step(tx, sel.bind(b))
+			}, nil)
+		} else {
+			err = step(sess, sel)
+		}
+		if err != nil {
+			return err
+		}
+		lastKey = nextKey
+
+		if n < chunkSize {
+			return nil
+		}
+		if remaining > 0 {
+			if remaining -= n; remaining == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// isOrdered reports whether the query has an ORDER BY clause with at least
+// one column.
+func isOrdered(sq *selectorQuery) bool {
+	if sq.orderBy == nil {
+		return false
+	}
+	columns, ok := sq.orderBy.SortColumns.(*exql.SortColumns)
+	return !ok || len(columns.Columns) > 0
+}
+
+// bind returns a copy of the selector that is executed by the given builder.
+func (sel *selector) bind(b *sqlBuilder) *selector {
+	root := &selector{builder: b}
+	return root.frame(func(sq *selectorQuery) error {
+		built, err := sel.build()
+		if err != nil {
+			return err
+		}
+		*sq = *built
+		return nil
+	})
+}
+
+// chunkKey returns the value the key column has on the last item of the
+// given chunk.
+func chunkKey(mapper *reflectx.Mapper, batch interface{}, key string) (interface{}, error) {
+	// The key may be qualified with the name of its table.
+	name := key
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	items := reflect.ValueOf(batch).Elem()
+	item := reflect.Indirect(items.Index(items.Len() - 1))
+
+	switch item.Kind() {
+	case reflect.Map:
+		if item.Type().Key().Kind() == reflect.String {
+			value := item.MapIndex(reflect.ValueOf(name).Convert(item.Type().Key()))
+			if value.IsValid() {
+				return value.Interface(), nil
+			}
+		}
+	case reflect.Struct:
+		if fi, ok := mapper.TypeMap(item.Type()).Lookup(name); ok {
+			return reflectx.FieldByIndexesReadOnly(item, fi.Index).Interface(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("upper: chunk items have no value for key %q", key)
+}
@@ -0,0 +1,227 @@
+package sqlbuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	db "github.com/upper/db/v4"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+var errExpectingCopyColumns = errors.New(`columns must be given to copy rows of values`)
+
+// CopySource reads the rows of a bulk load from a slice, a channel or a
+// db.Iterator and maps each one of them to the values of a fixed list of
+// columns. Rows can be maps, structs, pointers to either of them or
+// []interface{} tuples. CopySource satisfies pgx's CopyFromSource interface.
+type CopySource struct {
+	ctx     context.Context
+	mapper  *reflectx.Mapper
+	columns []string
+	derived bool
+
+	next  func() (interface{}, bool, error)
+	close func() error
+
+	peeked  bool
+	current interface{}
+	err     error
+}
+
+// NewCopySource returns a CopySource that reads the given rows. If no columns
+// are given they're taken from the first row, like Insert does, and a later
+// row that maps to a column the first row doesn't have is an error.
+func NewCopySource(ctx context.Context, mapper *reflectx.Mapper, rows interface{}, columns []string) (*CopySource, error) {
+	src := &CopySource{
+		ctx:     ctx,
+		mapper:  mapper,
+		columns: columns,
+		close:   func() error { return nil },
+	}
+
+	if iter, ok := rows.(db.Iterator); ok {
+		src.close = iter.Close
+		src.next = func() (interface{}, bool, error) {
+			row := map[string]interface{}{}
+			if !iter.Next(&row) {
+				return nil, false, iter.Err()
+			}
+			return row, true, nil
+		}
+	} else {
+		v := reflect.ValueOf(rows)
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			i := 0
+			src.next = func() (interface{}, bool, error) {
+				if i >= v.Len() {
+					return nil, false, nil
+				}
+				i++
+				return v.Index(i - 1).Interface(), true, nil
+			}
+		case reflect.Chan:
+			cases := []reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: v},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			}
+			src.next = func() (interface{}, bool, error) {
+				chosen, row, ok := reflect.Select(cases)
+				if chosen == 1 {
+					return nil, false, ctx.Err()
+				}
+				if !ok {
+					return nil, false, nil
+				}
+				return row.Interface(), true, nil
+			}
+		default:
+			return nil, fmt.Errorf("upper: expecting a slice, a channel or an iterator of rows but got %T", rows)
+		}
+	}
+
+	if len(src.columns) > 0 {
+		return src, nil
+	}
+
+	// Peek the first row to find out the columns.
+	if !src.Next() {
+		return src, nil
+	}
+	src.peeked = true
+
+	if _, isTuple := src.current.([]interface{}); isTuple {
+		_ = src.Close()
+		return nil, errExpectingCopyColumns
+	}
+	columns, _, err := Map(src.current, &MapOptions{Mapper: mapper})
+	if err != nil {
+		_ = src.Close()
+		return nil, err
+	}
+	src.columns = columns
+	src.derived = true
+
+	return src, nil
+}
+
+// Columns returns the columns the rows are mapped to, it's empty if there are
+// no rows and no columns were given.
+func (src *CopySource) Columns() []string {
+	return src.columns
+}
+
+// Next advances to the next row, it returns false when there are no more rows
+// or when an error happened.
+func (src *CopySource) Next() bool {
+	if src.peeked {
+		src.peeked = false
+		return true
+	}
+	if src.err != nil {
+		return false
+	}
+	if src.err = src.ctx.Err(); src.err != nil {
+		return false
+	}
+
+	var ok bool
+	src.current, ok, src.err = src.next()
+	return ok && src.err == nil
+}
+
+// Values returns the values of the current row, in the same order as Columns.
+// Columns the row has no value for are NULL.
+func (src *CopySource) Values() ([]interface{}, error) {
+	if tuple, ok := src.current.([]interface{}); ok {
+		if len(tuple) != len(src.columns) {
+			return nil, fmt.Errorf("upper: expecting %d values to copy but got %d", len(src.columns), len(tuple))
+		}
+		return tuple, nil
+	}
+
+	if src.derived {
+		if err := src.checkColumns(); err != nil {
+			return nil, err
+		}
+	}
+
+	itemV := reflect.Indirect(reflect.ValueOf(src.current))
+	values := make([]interface{}, len(src.columns))
+
+	switch itemV.Kind() {
+	case reflect.Struct:
+		fieldMap := src.mapper.TypeMap(itemV.Type()).Names
+		for i, column := range src.columns {
+			fi, ok := fieldMap[column]
+			if !ok {
+				continue
+			}
+			fld := reflectx.FieldByIndexesReadOnly(itemV, fi.Index)
+			if fld.Kind() == reflect.Ptr && fld.IsNil() {
+				continue
+			}
+			v, err := marshalField(fi, fld.Interface(), &MapOptions{Mapper: src.mapper})
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+	case reflect.Map:
+		if itemV.Type().Key().Kind() != reflect.String {
+			return nil, ErrExpectingMapOrStruct
+		}
+		for i, column := range src.columns {
+			value := itemV.MapIndex(reflect.ValueOf(column).Convert(itemV.Type().Key()))
+			if !value.IsValid() {
+				continue
+			}
+			v, err := marshal(value.Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+	default:
+		return nil, ErrExpectingMapOrStruct
+	}
+
+	return values, nil
+}
+
+// checkColumns makes sure the current row doesn't map to a column that was
+// not taken from the first row, its value would be lost otherwise.
+func (src *CopySource) checkColumns() error {
+	columns, _, err := Map(src.current, &MapOptions{Mapper: src.mapper})
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		found := false
+		for i := range src.columns {
+			if src.columns[i] == column {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("upper: row to copy has a value for column %q, which is not in %v", column, src.columns)
+		}
+	}
+	return nil
+}
+
+// Err returns the error that stopped Next, if any.
+func (src *CopySource) Err() error {
+	return src.err
+}
+
+// Close frees up the iterator the rows were read from, if any.
+func (src *CopySource) Close() error {
+	return src.close()
+}
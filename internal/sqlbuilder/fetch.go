@@ -23,6 +23,10 @@ package sqlbuilder
 
 import (
 	"reflect"
+	"slices"
+	"sync"
+	"time"
+	"unsafe"
 
 	"database/sql"
 	"database/sql/driver"
@@ -35,6 +39,10 @@ type sessValueConverter interface {
 	ConvertValue(interface{}) interface{}
 }
 
+type sessStrictMapper interface {
+	StrictMappingEnabled() bool
+}
+
 type valueConverter interface {
 	ConvertValue(in interface{}) (out interface {
 		sql.Scanner
@@ -42,8 +50,67 @@ type valueConverter interface {
 	})
 }
 
+// Mapper maps the fields of structs to columns for sessions that don't
+// configure a field mapping.
 var Mapper = reflectx.NewMapper("db")
 
+type sessFieldMapper interface {
+	FieldMapper() *reflectx.Mapper
+}
+
+// mapperKey identifies a field mapping. NameFunc is identified by the address
+// of its closure, which tells apart closures of the same function literal,
+// unlike reflect.Value.Pointer. Cached mappers keep their NameFunc alive, so
+// the address can't be reused by another function.
+type mapperKey struct {
+	tagName         string
+	nameFunc        uintptr
+	caseInsensitive bool
+}
+
+// mappers holds the mappers built by NewMapper, by mapperKey.
+var mappers sync.Map
+
+// NewMapper returns a mapper that maps the fields of structs to columns as
+// described by the given field mapping. Mappers are cached, so sessions with
+// the same field mapping share the cache of struct types; Mapper is returned
+// for the default field mapping.
+func NewMapper(mapping db.FieldMapping) *reflectx.Mapper {
+	tagName := mapping.TagName
+	if tagName == "" {
+		tagName = "db"
+	}
+	if tagName == "db" && mapping.NameFunc == nil && !mapping.CaseInsensitive {
+		return Mapper
+	}
+
+	key := mapperKey{tagName: tagName, caseInsensitive: mapping.CaseInsensitive}
+	if mapping.NameFunc != nil {
+		key.nameFunc = uintptr(*(*unsafe.Pointer)(unsafe.Pointer(&mapping.NameFunc)))
+	}
+	if mapper, ok := mappers.Load(key); ok {
+		return mapper.(*reflectx.Mapper)
+	}
+
+	mapper := reflectx.NewMapperTagFunc(tagName, mapping.NameFunc, nil)
+	if mapping.CaseInsensitive {
+		mapper.FoldCase()
+	}
+	cached, _ := mappers.LoadOrStore(key, mapper)
+	return cached.(*reflectx.Mapper)
+}
+
+// MapperOf returns the mapper of the given session, or Mapper if the session
+// does not have one.
+func MapperOf(sess interface{}) *reflectx.Mapper {
+	if m, ok := sess.(sessFieldMapper); ok {
+		if mapper := m.FieldMapper(); mapper != nil {
+			return mapper
+		}
+	}
+	return Mapper
+}
+
 // fetchRow receives a *sql.Rows value and tries to map all the rows into a
 // single struct given by the pointer `dst`.
 func fetchRow(iter *iterator, dst interface{}) error {
@@ -76,6 +143,10 @@ func fetchRow(iter *iterator, dst interface{}) error {
 	}
 
 	itemT := itemV.Type()
+	if err = checkMapping(iter, itemT, columns); err != nil {
+		return err
+	}
+
 	item, err := fetchResult(iter, itemT, columns)
 	if err != nil {
 		return err
@@ -122,6 +193,10 @@ func fetchRows(iter *iterator, dst interface{}) error {
 
 	reset(dst)
 
+	if err = checkMapping(iter, itemT, columns); err != nil {
+		return err
+	}
+
 	for rows.Next() {
 		item, err := fetchResult(iter, itemT, columns)
 		if err != nil {
@@ -145,6 +220,13 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 	var err error
 	rows := iter.cursor
 
+	switch {
+	case itemT == tupleType:
+		return fetchTuple(iter, columns)
+	case isScalar(itemT):
+		return fetchScalar(iter, itemT, columns)
+	}
+
 	objT := itemT
 
 	switch objT.Kind() {
@@ -166,11 +248,11 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 	case reflect.Struct:
 
 		values := make([]interface{}, len(columns))
-		typeMap := Mapper.TypeMap(itemT)
-		fieldMap := typeMap.Names
+		typeMap := MapperOf(iter.sess).TypeMap(itemT)
+		optionals := optionalStructs{}
 
 		for i, k := range columns {
-			fi, ok := fieldMap[k]
+			fi, ok := typeMap.Lookup(k)
 			if !ok {
 				values[i] = new(interface{})
 				continue
@@ -183,31 +265,20 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 
 			f := reflectx.FieldByIndexes(item, fi.Index)
 
-			// TODO: type switch + scanner
-
-			if w, ok := f.Interface().(valueConverter); ok {
-				wrapper := w.ConvertValue(f.Addr().Interface())
-				z := reflect.ValueOf(wrapper)
-				values[i] = z.Interface()
-				continue
-			} else {
-				values[i] = f.Addr().Interface()
+			dest, err := fieldDestination(iter, fi, f)
+			if err != nil {
+				return item, err
 			}
-
-			if unmarshaler, ok := values[i].(db.Unmarshaler); ok {
-				values[i] = scanner{unmarshaler}
-				continue
-			}
-
-			if converter, ok := iter.sess.(sessValueConverter); ok {
-				values[i] = converter.ConvertValue(values[i])
-				continue
+			if index := optionalStructIndex(objT, fi.Index); index != nil {
+				dest = optionals.destination(index, dest)
 			}
+			values[i] = dest
 		}
 
 		if err = rows.Scan(values...); err != nil {
 			return item, err
 		}
+		optionals.finish(item)
 
 	case reflect.Map:
 
@@ -237,6 +308,255 @@ func fetchResult(iter *iterator, itemT reflect.Type, columns []string) (reflect.
 	return item, nil
 }
 
+var (
+	tupleType   = reflect.TypeOf([]interface{}{})
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isScalar reports whether values of type t hold a single column instead of
+// being mapped to columns like maps and structs are.
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Ptr:
+		return false
+	case reflect.Struct:
+		return t == timeType || reflect.PointerTo(t).Implements(scannerType)
+	}
+	return t != tupleType
+}
+
+// fetchScalar scans the only column of the current row into a value of type
+// itemT. The returned value is a pointer to it, unless itemT is a pointer
+// itself.
+func fetchScalar(iter *iterator, itemT reflect.Type, columns []string) (reflect.Value, error) {
+	if len(columns) != 1 {
+		return reflect.Value{}, ErrExpectingSingleColumn
+	}
+
+	item := reflect.New(itemT)
+	if err := iter.cursor.Scan(valueDestination(iter, item.Elem())); err != nil {
+		return item, err
+	}
+
+	if itemT.Kind() == reflect.Ptr {
+		return item.Elem(), nil
+	}
+	return item, nil
+}
+
+// fetchTuple scans the columns of the current row into a slice, by position.
+func fetchTuple(iter *iterator, columns []string) (reflect.Value, error) {
+	values := make([]interface{}, len(columns))
+	for i := range values {
+		values[i] = new(interface{})
+	}
+
+	item := reflect.New(tupleType)
+	if err := iter.cursor.Scan(values...); err != nil {
+		return item, err
+	}
+
+	tuple := make([]interface{}, len(values))
+	for i := range values {
+		tuple[i] = *(values[i].(*interface{}))
+	}
+	item.Elem().Set(reflect.ValueOf(tuple))
+
+	return item, nil
+}
+
+// strictMapping reports whether the rows of the iterator must map exactly into
+// structs.
+func (iter *iterator) strictMapping() bool {
+	if iter.strict != nil {
+		return *iter.strict
+	}
+	if s, ok := iter.sess.(sessStrictMapper); ok {
+		return s.StrictMappingEnabled()
+	}
+	return false
+}
+
+// checkMapping returns a *db.MappingError if strict mapping is enabled and
+// the given columns don't match the fields of itemT, when it's a struct.
+func checkMapping(iter *iterator, itemT reflect.Type, columns []string) error {
+	if !iter.strictMapping() || isScalar(itemT) || reflectx.Deref(itemT).Kind() != reflect.Struct {
+		return nil
+	}
+
+	typeMap := MapperOf(iter.sess).TypeMap(itemT)
+	mappingErr := &db.MappingError{Type: reflectx.Deref(itemT).String()}
+
+	selected := make(map[*reflectx.FieldInfo]bool, len(columns))
+	for _, column := range columns {
+		fi, ok := typeMap.Lookup(column)
+		if !ok {
+			mappingErr.UnmappedColumns = append(mappingErr.UnmappedColumns, column)
+			continue
+		}
+		selected[fi] = true
+	}
+
+	for _, fi := range typeMap.Index {
+		if fi.Name == "" || fi.Embedded || selected[fi] {
+			continue
+		}
+		if (!isScalar(fi.Field.Type) && hasMappedChildren(fi)) || isOptionalField(fi) {
+			continue
+		}
+		mappingErr.MissingFields = append(mappingErr.MissingFields, fi.Path)
+	}
+
+	if len(mappingErr.UnmappedColumns) > 0 || len(mappingErr.MissingFields) > 0 {
+		return mappingErr
+	}
+	return nil
+}
+
+// hasMappedChildren reports whether fi is a struct with fields mapped to
+// columns of their own, in which case fi itself is not mapped to a column.
+func hasMappedChildren(fi *reflectx.FieldInfo) bool {
+	for _, child := range fi.Children {
+		if child == nil {
+			continue
+		}
+		if (child.Name != "" && !child.Embedded) || hasMappedChildren(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOptionalField reports whether fi, or the struct that holds it, is tagged
+// with the optional option.
+func isOptionalField(fi *reflectx.FieldInfo) bool {
+	for ; fi != nil; fi = fi.Parent {
+		if _, ok := fi.Options["optional"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldDestination returns the value a column is scanned into in order to
+// set the given struct field.
+func fieldDestination(iter *iterator, fi *reflectx.FieldInfo, f reflect.Value) (interface{}, error) {
+	if name, hasCodec := fi.Options["codec"]; hasCodec {
+		codec, err := lookupCodec(name)
+		if err != nil {
+			return nil, err
+		}
+		return codecScanner{codec: codec, dst: f.Addr().Interface()}, nil
+	}
+	return valueDestination(iter, f), nil
+}
+
+// valueDestination returns the value a column is scanned into in order to set
+// the addressable value f.
+func valueDestination(iter *iterator, f reflect.Value) interface{} {
+	// TODO: type switch + scanner
+
+	if w, ok := f.Interface().(valueConverter); ok {
+		return w.ConvertValue(f.Addr().Interface())
+	}
+
+	dest := f.Addr().Interface()
+
+	if unmarshaler, ok := dest.(db.Unmarshaler); ok {
+		return scanner{unmarshaler}
+	}
+
+	if converter, ok := iter.sess.(sessValueConverter); ok {
+		return converter.ConvertValue(dest)
+	}
+
+	return dest
+}
+
+// optionalStructIndex returns the index of the outermost field of t that is a
+// pointer to a struct and holds the field with the given index, if any.
+func optionalStructIndex(t reflect.Type, index []int) []int {
+	for i := 0; i < len(index)-1; i++ {
+		f := t.Field(index[i])
+		if f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct && !f.Anonymous {
+			return index[:i+1]
+		}
+		t = reflectx.Deref(f.Type)
+	}
+	return nil
+}
+
+// optionalStruct tracks the columns of a struct referenced by a pointer
+// field. The pointer is set to nil if all of them are NULL, which is what
+// LEFT JOIN returns for the rows it can't match.
+type optionalStruct struct {
+	index []int
+	valid bool
+	sets  []func()
+}
+
+type optionalStructs []*optionalStruct
+
+// destination wraps dest in order to find out whether the column it's
+// scanned from is NULL.
+func (o *optionalStructs) destination(index []int, dest interface{}) interface{} {
+	var opt *optionalStruct
+	for _, candidate := range *o {
+		if slices.Equal(candidate.index, index) {
+			opt = candidate
+			break
+		}
+	}
+	if opt == nil {
+		opt = &optionalStruct{index: index}
+		*o = append(*o, opt)
+	}
+
+	if s, ok := dest.(sql.Scanner); ok {
+		return &optionalScanner{opt: opt, dest: s}
+	}
+
+	// Pointers to pointers are left nil when NULL is scanned into them.
+	ptr := reflect.New(reflect.TypeOf(dest))
+	opt.sets = append(opt.sets, func() {
+		if !ptr.Elem().IsNil() {
+			opt.valid = true
+			reflect.ValueOf(dest).Elem().Set(ptr.Elem().Elem())
+		}
+	})
+	return ptr.Interface()
+}
+
+// finish sets the scanned values and clears the pointers to structs whose
+// columns were all NULL.
+func (o optionalStructs) finish(item reflect.Value) {
+	for _, opt := range o {
+		for _, set := range opt.sets {
+			set()
+		}
+		if !opt.valid {
+			f := reflectx.FieldByIndexesReadOnly(item, opt.index)
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+}
+
+type optionalScanner struct {
+	opt  *optionalStruct
+	dest sql.Scanner
+}
+
+func (s *optionalScanner) Scan(v interface{}) error {
+	if v != nil {
+		s.opt.valid = true
+	}
+	return s.dest.Scan(v)
+}
+
 func reset(data interface{}) {
 	// Resetting element.
 	v := reflect.ValueOf(data).Elem()
@@ -7,6 +7,7 @@ import (
 
 	"github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/immutable"
+	"github.com/upper/db/v4/internal/reflectx"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
 )
 
@@ -20,13 +21,13 @@ type inserterQuery struct {
 	amendFn        func(string) string
 }
 
-func (iq *inserterQuery) processValues() ([]*exql.Values, []interface{}, error) {
+func (iq *inserterQuery) processValues(mapper *reflectx.Mapper) ([]*exql.Values, []interface{}, error) {
 	var values []*exql.Values
 	var arguments []interface{}
 
-	var mapOptions *MapOptions
+	mapOptions := &MapOptions{Mapper: mapper}
 	if len(iq.enqueuedValues) > 1 {
-		mapOptions = &MapOptions{IncludeZeroed: true, IncludeNil: true}
+		mapOptions.IncludeZeroed, mapOptions.IncludeNil = true, true
 	}
 
 	for _, enqueuedValue := range iq.enqueuedValues {
@@ -204,7 +205,7 @@ func (ins *inserter) Iterator() db.Iterator {
 
 func (ins *inserter) IteratorContext(ctx context.Context) db.Iterator {
 	rows, err := ins.QueryContext(ctx)
-	return &iterator{ins.SQL().sess, rows, err}
+	return &iterator{ins.SQL().sess, rows, err, nil}
 }
 
 func (ins *inserter) Into(table string) db.Inserter {
@@ -242,7 +243,7 @@ func (ins *inserter) build() (*inserterQuery, error) {
 		return nil, err
 	}
 	ret := iq.(*inserterQuery)
-	ret.values, ret.arguments, err = ret.processValues()
+	ret.values, ret.arguments, err = ret.processValues(MapperOf(ins.SQL().sess))
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +171,7 @@ func (pag *paginator) Iterator() db.Iterator {
 	pq, err := pag.buildWithCursor()
 	if err != nil {
 		sess := pq.sel.(*selector).SQL().sess
-		return &iterator{sess, nil, err}
+		return &iterator{sess, nil, err, nil}
 	}
 	return pq.sel.Iterator()
 }
@@ -180,7 +180,7 @@ func (pag *paginator) IteratorContext(ctx context.Context) db.Iterator {
 	pq, err := pag.buildWithCursor()
 	if err != nil {
 		sess := pq.sel.(*selector).SQL().sess
-		return &iterator{sess, nil, err}
+		return &iterator{sess, nil, err, nil}
 	}
 	return pq.sel.IteratorContext(ctx)
 }
@@ -257,6 +257,14 @@ func (pag *paginator) PrepareContext(ctx context.Context) (*sql.Stmt, error) {
 	return pq.sel.PrepareContext(ctx)
 }
 
+func (pag *paginator) Explain(ctx context.Context, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	pq, err := pag.buildWithCursor()
+	if err != nil {
+		return nil, err
+	}
+	return pq.sel.Explain(ctx, opts)
+}
+
 func (pag *paginator) TotalEntries() (uint64, error) {
 	pq, err := pag.build()
 	if err != nil {
@@ -23,6 +23,7 @@ package sqlbuilder
 
 import (
 	"database/sql"
+	"fmt"
 
 	db "github.com/upper/db/v4"
 )
@@ -36,3 +37,23 @@ func (u scanner) Scan(v interface{}) error {
 }
 
 var _ sql.Scanner = scanner{}
+
+// codecScanner decodes the scanned value with the codec of a field.
+type codecScanner struct {
+	codec db.Codec
+	dst   interface{}
+}
+
+func (s codecScanner) Scan(v interface{}) error {
+	return s.codec.Decode(v, s.dst)
+}
+
+var _ sql.Scanner = codecScanner{}
+
+func lookupCodec(name string) (db.Codec, error) {
+	codec, ok := db.LookupCodec(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", db.ErrUnknownCodec, name)
+	}
+	return codec, nil
+}
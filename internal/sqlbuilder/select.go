@@ -5,11 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/upper/db/v4"
 	"github.com/upper/db/v4/internal/adapter"
 	"github.com/upper/db/v4/internal/immutable"
+	"github.com/upper/db/v4/internal/reflectx"
 	"github.com/upper/db/v4/internal/sqladapter/exql"
 )
 
@@ -38,6 +40,12 @@ type selectorQuery struct {
 	joinsArgs []interface{}
 
 	amendFn func(string) string
+
+	// strictMapping overrides the strict mapping setting of the session if
+	// it's not nil.
+	strictMapping *bool
+
+	mapper *reflectx.Mapper
 }
 
 func (sq *selectorQuery) and(b *sqlBuilder, terms ...interface{}) error {
@@ -80,6 +88,16 @@ func (sq *selectorQuery) statement() *exql.Statement {
 		stmt.Joins = exql.JoinConditions(sq.joins...)
 	}
 
+	if sq.columns != nil {
+		table := tableName(sq.table)
+		for _, column := range sq.columns.Columns {
+			if sc, ok := column.(*structColumns); ok {
+				sc.table = table
+				sc.mapper = sq.mapper
+			}
+		}
+	}
+
 	stmt.SetAmendment(sq.amendFn)
 
 	return stmt
@@ -163,7 +181,7 @@ func (sel *selector) setColumns(columns ...interface{}) db.Selector {
 
 func (sel *selector) Columns(columns ...interface{}) db.Selector {
 	return sel.frame(func(sq *selectorQuery) error {
-		return sq.pushColumns(columns...)
+		return sq.pushColumns(sel.SQL().t.expandRawColumns(columns)...)
 	})
 }
 
@@ -215,6 +233,13 @@ func (sel *selector) Amend(fn func(string) string) db.Selector {
 	})
 }
 
+func (sel *selector) StrictMapping(value bool) db.Selector {
+	return sel.frame(func(sq *selectorQuery) error {
+		sq.strictMapping = &value
+		return nil
+	})
+}
+
 func (sel *selector) Arguments() []interface{} {
 	sq, err := sel.build()
 	if err != nil {
@@ -466,6 +491,25 @@ func (sel *selector) QueryContext(ctx context.Context) (*sql.Rows, error) {
 	return sel.SQL().sess.StatementQuery(ctx, sq.statement(), sq.arguments()...)
 }
 
+func (sel *selector) Explain(ctx context.Context, opts *db.ExplainOptions) (*db.QueryPlan, error) {
+	sess := sel.SQL().sess
+	explainer, ok := sess.(exprExplainer)
+	if !ok {
+		return nil, db.ErrNotSupportedByAdapter
+	}
+	sq, err := sel.build()
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = sess.Context()
+	}
+	if opts == nil {
+		opts = &db.ExplainOptions{}
+	}
+	return explainer.StatementExplain(ctx, sq.statement(), opts, sq.arguments()...)
+}
+
 func (sel *selector) Iterator() db.Iterator {
 	return sel.IteratorContext(sel.SQL().sess.Context())
 }
@@ -474,11 +518,11 @@ func (sel *selector) IteratorContext(ctx context.Context) db.Iterator {
 	sess := sel.SQL().sess
 	sq, err := sel.build()
 	if err != nil {
-		return &iterator{sess, nil, err}
+		return &iterator{sess, nil, err, nil}
 	}
 
 	rows, err := sess.StatementQuery(ctx, sq.statement(), sq.arguments()...)
-	return &iterator{sess, rows, err}
+	return &iterator{sess, rows, err, sq.strictMapping}
 }
 
 func (sel *selector) Paginate(pageSize uint) db.Paginator {
@@ -498,6 +542,7 @@ func (sel *selector) build() (*selectorQuery, error) {
 	if err != nil {
 		return nil, err
 	}
+	sq.(*selectorQuery).mapper = MapperOf(sel.SQL().sess)
 	return sq.(*selectorQuery), nil
 }
 
@@ -522,3 +567,82 @@ func (sel *selector) Fn(in interface{}) error {
 func (sel *selector) Base() interface{} {
 	return &selectorQuery{}
 }
+
+// structColumns represents the columns the fields of a struct are mapped to.
+// Columns are qualified with the table the struct is selected from, the
+// columns of nested structs with a prefix are aliased with their prefixed
+// names.
+type structColumns struct {
+	t      reflect.Type
+	table  string
+	mapper *reflectx.Mapper
+}
+
+func (sc *structColumns) columns() *exql.Columns {
+	fragments := []exql.Fragment{}
+
+	mapper := sc.mapper
+	if mapper == nil {
+		mapper = Mapper
+	}
+	for _, fi := range mapper.TypeMap(sc.t).Index {
+		if fi.Name == "" || fi.Embedded {
+			continue
+		}
+		if _, ok := fi.Options["prefix"]; ok {
+			continue
+		}
+
+		nested, prefixed := false, false
+		for parent := fi.Parent; parent != nil && parent.Index != nil; parent = parent.Parent {
+			if _, ok := parent.Options["prefix"]; ok {
+				prefixed = true
+			} else if !parent.Embedded {
+				nested = true
+			}
+		}
+		if nested {
+			continue
+		}
+
+		switch {
+		case prefixed && strings.Contains(fi.Path, "."):
+			fragments = append(fragments, exql.ColumnWithName(fi.Path+" AS "+fi.Path))
+		case !prefixed && sc.table != "":
+			fragments = append(fragments, exql.ColumnWithName(sc.table+"."+fi.Path+" AS "+fi.Path))
+		default:
+			fragments = append(fragments, exql.ColumnWithName(fi.Path))
+		}
+	}
+
+	return exql.JoinColumns(fragments...)
+}
+
+func (sc *structColumns) Hash() uint64 {
+	return sc.columns().Hash()
+}
+
+func (sc *structColumns) Compile(layout *exql.Template) (string, error) {
+	return sc.columns().Compile(layout)
+}
+
+// tableName returns the name or alias of the first of the given tables, if
+// it's given by name.
+func tableName(tables *exql.Columns) string {
+	if tables == nil || len(tables.Columns) == 0 {
+		return ""
+	}
+	column, ok := tables.Columns[0].(*exql.Column)
+	if !ok {
+		return ""
+	}
+	name, ok := column.Name.(string)
+	if !ok {
+		return ""
+	}
+	chunks := strings.Fields(name)
+	if len(chunks) == 0 {
+		return ""
+	}
+	return chunks[len(chunks)-1]
+}
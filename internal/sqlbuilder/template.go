@@ -21,7 +21,10 @@ func newTemplateWithUtils(template *exql.Template) *templateWithUtils {
 func (tu *templateWithUtils) PlaceholderValue(in interface{}) (exql.Fragment, []interface{}) {
 	switch t := in.(type) {
 	case *adapter.RawExpr:
-		return &exql.Raw{Value: t.Raw()}, t.Arguments()
+		raw, args := tu.expandColumns(t.Raw(), t.Arguments())
+		return &exql.Raw{Value: raw}, args
+	case *adapter.ColumnExpr:
+		return exql.ColumnWithName(t.Name()), nil
 	case *adapter.FuncExpr:
 		fnName := t.Name()
 		fnArgs := []interface{}{}
@@ -41,6 +44,79 @@ func (tu *templateWithUtils) PlaceholderValue(in interface{}) (exql.Fragment, []
 	}
 }
 
+// expandColumns replaces the placeholders of the given raw expression that
+// correspond to column references with the quoted name of the column. Question
+// marks within quoted strings or identifiers are not placeholders, nor is the
+// ?? escape, which is how operators like PostgreSQL's ?, ?| and ?& are written.
+func (tu *templateWithUtils) expandColumns(raw string, args []interface{}) (string, []interface{}) {
+	if !hasColumnExpr(args) {
+		return raw, args
+	}
+
+	var out strings.Builder
+	outArgs := make([]interface{}, 0, len(args))
+
+	var quote byte
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case quote != 0:
+			// A doubled quote is an escaped quote, which toggles quote twice.
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?' && i+1 < len(raw) && raw[i+1] == '?':
+			out.WriteString("??")
+			i++
+			continue
+		case c == '?' && len(args) > 0:
+			arg := args[0]
+			args = args[1:]
+			if column, ok := arg.(*adapter.ColumnExpr); ok {
+				if compiled, err := exql.ColumnWithName(column.Name()).Compile(tu.Template); err == nil {
+					out.WriteString(compiled)
+					continue
+				}
+			}
+			outArgs = append(outArgs, arg)
+		}
+		out.WriteByte(c)
+	}
+
+	return out.String(), append(outArgs, args...)
+}
+
+// expandRawColumns quotes the column references given as arguments of the raw
+// expressions within columns, like db.Raw("SUM(?)", db.Column("amount")).
+func (tu *templateWithUtils) expandRawColumns(columns []interface{}) []interface{} {
+	var expanded []interface{}
+	for i := range columns {
+		raw, ok := columns[i].(*adapter.RawExpr)
+		if !ok || !hasColumnExpr(raw.Arguments()) {
+			continue
+		}
+		if expanded == nil {
+			expanded = append([]interface{}(nil), columns...)
+		}
+		expanded[i] = adapter.NewRawExpr(tu.expandColumns(raw.Raw(), raw.Arguments()))
+	}
+	if expanded == nil {
+		return columns
+	}
+	return expanded
+}
+
+func hasColumnExpr(args []interface{}) bool {
+	for i := range args {
+		if _, ok := args[i].(*adapter.ColumnExpr); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // toWhereWithArguments converts the given parameters into a exql.Where value.
 func (tu *templateWithUtils) toWhereWithArguments(term interface{}) (where exql.Where, args []interface{}) {
 	args = []interface{}{}
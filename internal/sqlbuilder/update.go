@@ -108,7 +108,7 @@ func (upd *updater) Set(terms ...interface{}) db.Updater {
 		}
 
 		if len(terms) == 1 {
-			ff, vv, err := Map(terms[0], nil)
+			ff, vv, err := Map(terms[0], &MapOptions{Mapper: MapperOf(upd.SQL().sess)})
 			if err == nil && len(ff) > 0 {
 				cvs := make([]exql.Fragment, 0, len(ff))
 				args := make([]interface{}, 0, len(vv))
@@ -257,6 +257,9 @@ type QueryStatus struct {
 	End   time.Time
 
 	Context context.Context
+
+	// SkipStack prevents String() from capturing the caller's stack.
+	SkipStack bool
 }
 
 func (q *QueryStatus) Query() string {
@@ -295,8 +298,10 @@ func (q *QueryStatus) String() string {
 		lines = append(lines, fmt.Sprintf(fmtLogArgs, q.Args))
 	}
 
-	if stack := q.Stack(); len(stack) > 0 {
-		lines = append(lines, fmt.Sprintf(fmtLogStack, "\n\t"+strings.Join(stack, "\n\t")))
+	if !q.SkipStack {
+		if stack := q.Stack(); len(stack) > 0 {
+			lines = append(lines, fmt.Sprintf(fmtLogStack, "\n\t"+strings.Join(stack, "\n\t")))
+		}
 	}
 
 	if q.RowsAffected != nil {
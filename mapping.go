@@ -0,0 +1,83 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FieldMapping defines how SQL adapters map the fields of structs to columns,
+// see Settings.SetFieldMapping.
+type FieldMapping struct {
+	// TagName is the name of the struct tag that holds the column names and
+	// options of fields, "db" if empty.
+	TagName string
+
+	// NameFunc returns the column of a field from its name. It's used for
+	// untagged fields and for fields whose tag only holds options, like
+	// `db:",omitempty"`. Untagged fields are not mapped if NameFunc is nil.
+	NameFunc func(field string) string
+
+	// CaseInsensitive makes the columns of result sets match fields
+	// regardless of case when there's no exact match.
+	CaseInsensitive bool
+}
+
+// SnakeCase converts a field name into snake case, like "user_id" for
+// "UserID". It can be used as the NameFunc of a FieldMapping.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// CamelCase converts a field name into lower camel case, like "userID" for
+// "UserID" or "httpStatus" for "HTTPStatus". It can be used as the NameFunc
+// of a FieldMapping.
+func CamelCase(name string) string {
+	runes := []rune(name)
+
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	// The last upper case letter of an acronym starts the next word.
+	if n > 1 && n < len(runes) && unicode.IsLower(runes[n]) {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for in, out := range map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"UserID":     "user_id",
+		"HTTPStatus": "http_status",
+		"Address2":   "address2",
+		"CreatedAt":  "created_at",
+		"already_ok": "already_ok",
+	} {
+		assert.Equal(t, out, SnakeCase(in), in)
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	for in, out := range map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"UserID":     "userID",
+		"HTTPStatus": "httpStatus",
+		"CreatedAt":  "createdAt",
+		"lower":      "lower",
+	} {
+		assert.Equal(t, out, CamelCase(in), in)
+	}
+}
+
+func TestFieldMappingSettings(t *testing.T) {
+	settings := NewSettings()
+	assert.Equal(t, "db", settings.FieldMapping().TagName)
+	assert.Nil(t, settings.FieldMapping().NameFunc)
+
+	settings.SetFieldMapping(FieldMapping{NameFunc: SnakeCase, CaseInsensitive: true})
+	mapping := settings.FieldMapping()
+	assert.Equal(t, "db", mapping.TagName)
+	assert.Equal(t, "user_id", mapping.NameFunc("UserID"))
+	assert.True(t, mapping.CaseInsensitive)
+}
@@ -22,7 +22,8 @@
 package db
 
 // Marshaler is the interface implemented by struct fields that can transform
-// themselves into values to be stored in a database.
+// themselves into values to be stored in a database. Fields of types that
+// can't be changed can be transformed with a Codec instead.
 type Marshaler interface {
 	// MarshalDB returns the internal database representation of the Go value.
 	MarshalDB() (interface{}, error)
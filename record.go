@@ -20,6 +20,10 @@
 
 package db
 
+import (
+	"context"
+)
+
 // Record is the equivalence between concrete database schemas and Go values.
 type Record interface {
 	Store(sess Session) Store
@@ -34,7 +38,8 @@ type HasConstraints interface {
 // Validator is an interface for records that defines an (optional) Validate
 // method that is called before persisting a record (creating or updating).  If
 // Validate returns an error the current operation is cancelled and rolled
-// back.
+// back. Validate is called after the record passes the validation rules declared
+// by its struct tags, see the ValidateSession function.
 type Validator interface {
 	Validate() error
 }
@@ -80,3 +85,113 @@ type BeforeDeleteHook interface {
 type AfterDeleteHook interface {
 	AfterDelete(Session) error
 }
+
+// BeforeSaveHook is an interface for records that defines a BeforeSave method
+// that is called by Session.Save before validating and creating or updating a
+// record. If BeforeSave returns an error the save process is cancelled and
+// rolled back.
+type BeforeSaveHook interface {
+	BeforeSave(Session) error
+}
+
+// AfterSaveHook is an interface for records that defines an AfterSave method
+// that is called by Session.Save after creating or updating a record. If
+// AfterSave returns an error the save process is cancelled and rolled back.
+type AfterSaveHook interface {
+	AfterSave(Session) error
+}
+
+// AfterFindHook is an interface for records that defines an AfterFind method
+// that is called after a record is fetched by Session.Get or by the One, All
+// and Next methods of a Result. If AfterFind returns an error the fetch
+// returns that error.
+type AfterFindHook interface {
+	AfterFind(Session) error
+}
+
+// BeforeCreateContextHook is like BeforeCreateHook but BeforeCreateContext
+// also receives the context of the session. If a record implements both
+// interfaces only BeforeCreateContext is called, the same applies to the
+// other context-aware hooks.
+type BeforeCreateContextHook interface {
+	BeforeCreateContext(context.Context, Session) error
+}
+
+// AfterCreateContextHook is like AfterCreateHook but AfterCreateContext also
+// receives the context of the session.
+type AfterCreateContextHook interface {
+	AfterCreateContext(context.Context, Session) error
+}
+
+// BeforeUpdateContextHook is like BeforeUpdateHook but BeforeUpdateContext
+// also receives the context of the session.
+type BeforeUpdateContextHook interface {
+	BeforeUpdateContext(context.Context, Session) error
+}
+
+// AfterUpdateContextHook is like AfterUpdateHook but AfterUpdateContext also
+// receives the context of the session.
+type AfterUpdateContextHook interface {
+	AfterUpdateContext(context.Context, Session) error
+}
+
+// BeforeDeleteContextHook is like BeforeDeleteHook but BeforeDeleteContext
+// also receives the context of the session.
+type BeforeDeleteContextHook interface {
+	BeforeDeleteContext(context.Context, Session) error
+}
+
+// AfterDeleteContextHook is like AfterDeleteHook but AfterDeleteContext also
+// receives the context of the session.
+type AfterDeleteContextHook interface {
+	AfterDeleteContext(context.Context, Session) error
+}
+
+// BeforeSaveContextHook is like BeforeSaveHook but BeforeSaveContext also
+// receives the context of the session.
+type BeforeSaveContextHook interface {
+	BeforeSaveContext(context.Context, Session) error
+}
+
+// AfterSaveContextHook is like AfterSaveHook but AfterSaveContext also
+// receives the context of the session.
+type AfterSaveContextHook interface {
+	AfterSaveContext(context.Context, Session) error
+}
+
+// AfterFindContextHook is like AfterFindHook but AfterFindContext also
+// receives the context of the session.
+type AfterFindContextHook interface {
+	AfterFindContext(context.Context, Session) error
+}
+
+// TrackedRecord is implemented by records that keep a snapshot of the values
+// of their columns, which sessions with record tracking enabled use to find
+// out which columns changed. Records can implement it by embedding Tracked.
+type TrackedRecord interface {
+	Record
+
+	// Snapshot returns the column values the record was fetched or saved
+	// with, it's nil if the record has no snapshot.
+	Snapshot() map[string]interface{}
+
+	// SetSnapshot replaces the snapshot of the record.
+	SetSnapshot(map[string]interface{})
+}
+
+// Tracked can be embedded into a record to keep its snapshot. Since the
+// snapshot is part of the record, overwriting the record with a new value
+// discards it as well.
+type Tracked struct {
+	snapshot map[string]interface{}
+}
+
+// Snapshot returns the column values the record was fetched or saved with.
+func (t *Tracked) Snapshot() map[string]interface{} {
+	return t.snapshot
+}
+
+// SetSnapshot replaces the snapshot of the record.
+func (t *Tracked) SetSnapshot(snapshot map[string]interface{}) {
+	t.snapshot = snapshot
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+// RelationKind defines how the items of two collections are related.
+type RelationKind uint8
+
+// Relation kinds.
+const (
+	// HasOne means that one item of the related collection references the
+	// item through its foreign key.
+	HasOne RelationKind = iota + 1
+
+	// HasMany means that many items of the related collection reference the
+	// item through their foreign key.
+	HasMany
+
+	// BelongsTo means that the item references one item of the related
+	// collection through its foreign key.
+	BelongsTo
+
+	// ManyToMany means that items of both collections reference each other
+	// through a join collection.
+	ManyToMany
+)
+
+// Relation describes how the items of a collection are related to the items
+// of another collection. Empty key names default to "id".
+type Relation struct {
+	Kind RelationKind
+
+	// Collection is the name of the related collection.
+	Collection string
+
+	// ForeignKey is the column of the related collection that references
+	// LocalKey (HasOne and HasMany), or the column of the item that
+	// references RelatedKey (BelongsTo).
+	ForeignKey string
+
+	// LocalKey is the column of the item that is referenced by the related
+	// items (HasOne, HasMany and ManyToMany).
+	LocalKey string
+
+	// RelatedKey is the column of the related collection that is referenced by
+	// the item (BelongsTo and ManyToMany).
+	RelatedKey string
+
+	// JoinCollection is the name of the collection that joins both sides of a
+	// ManyToMany relation.
+	JoinCollection string
+
+	// JoinForeignKey is the column of the join collection that references
+	// LocalKey.
+	JoinForeignKey string
+
+	// JoinRelatedKey is the column of the join collection that references
+	// RelatedKey.
+	JoinRelatedKey string
+}
+
+// HasRelations is an interface for records or stores that declare how their
+// items are related to items of other collections. Relations are keyed by the
+// name of the struct field that holds the related items, this field can be a
+// struct or a pointer to struct (HasOne and BelongsTo) or a slice of them
+// (HasMany and ManyToMany).
+//
+// Example:
+//
+//	func (*Order) Relations() map[string]db.Relation {
+//		return map[string]db.Relation{
+//			"Customer": {Kind: db.BelongsTo, Collection: "customers", ForeignKey: "customer_id"},
+//			"Items":    {Kind: db.HasMany, Collection: "items", ForeignKey: "order_id"},
+//		}
+//	}
+//
+// Related items are loaded with Result.Preload.
+type HasRelations interface {
+	Relations() map[string]Relation
+}
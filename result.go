@@ -22,6 +22,7 @@
 package db
 
 import (
+	"context"
 	"database/sql/driver"
 )
 
@@ -60,13 +61,93 @@ type Result interface {
 	GroupBy(...interface{}) Result
 
 	// Delete deletes all items within the result set. `Offset()` and `Limit()`
-	// are not honoured by `Delete()`.
+	// are not honoured by `Delete()`. On soft delete collections, Delete sets
+	// the soft delete column of the items instead of removing them.
 	Delete() error
 
+	// HardDelete permanently removes all items within the result set, even on
+	// soft delete collections. Use `WithDeleted()` or `OnlyDeleted()` to
+	// include items that were already soft deleted.
+	//
+	// Example:
+	//
+	//   err = accounts.Find().OnlyDeleted().HardDelete()
+	HardDelete() error
+
+	// DeleteCount is like Delete but it also returns the number of items that
+	// were deleted, or soft deleted.
+	//
+	// Example:
+	//
+	//   err = db.MustAffect(accounts.Find(id).DeleteCount())
+	DeleteCount() (uint64, error)
+
+	// HardDeleteCount is like HardDelete but it also returns the number of
+	// items that were removed.
+	HardDeleteCount() (uint64, error)
+
+	// WithDeleted makes the result set include soft deleted items. It has no
+	// effect on collections without a soft delete column.
+	WithDeleted() Result
+
+	// Unscoped lifts the conditions the collection was scoped with, see
+	// Collection.Scope. Soft deleted items are still hidden, use WithDeleted
+	// as well to include them.
+	Unscoped() Result
+
+	// StrictMapping overrides the strict mapping setting of the session for
+	// this result set, see Settings.SetStrictMapping.
+	StrictMapping(bool) Result
+
+	// Preload makes All() and One() load the given relations of the fetched
+	// items, relations are declared by records or stores that satisfy
+	// HasRelations. Related items are fetched in batches using IN queries
+	// instead of one query per item. Nested relations are separated by dots.
+	//
+	// Example:
+	//
+	//   var orders []Order
+	//   res := sess.Collection("orders").Find()
+	//   err = res.Preload("Customer", "Items.Product").All(&orders)
+	Preload(relations ...string) Result
+
+	// OnlyDeleted makes the result set include soft deleted items only. The
+	// result set fails with ErrMissingSoftDeleteColumn on collections without
+	// a soft delete column.
+	OnlyDeleted() Result
+
 	// Update modifies all items within the result set. `Offset()` and `Limit()`
 	// are not honoured by `Update()`.
+	//
+	// Values of maps can be expressions that are evaluated by the database,
+	// like Raw, Func or Column.
+	//
+	// Example:
+	//
+	//   err = res.Update(map[string]interface{}{
+	//     "total":     db.Raw("? * ?", db.Column("price"), 2),
+	//     "synced_at": db.Column("updated_at"),
+	//   })
 	Update(interface{}) error
 
+	// Increment atomically adds n to the given numeric column of all items
+	// within the result set. `Offset()` and `Limit()` are not honoured by
+	// `Increment()`.
+	//
+	// Example:
+	//
+	//   err = posts.Find(id).Increment("views", 1)
+	Increment(column string, n interface{}) error
+
+	// Decrement atomically subtracts n from the given numeric column of all
+	// items within the result set, see Increment.
+	Decrement(column string, n interface{}) error
+
+	// UpdateCount is like Update but it also returns the number of items that
+	// were modified. Note that MySQL only counts rows whose values actually
+	// changed, unless the clientFoundRows option is set on the connection.
+	UpdateCount(interface{}) (uint64, error)
+
 	// Count returns the number of items that match the set conditions.
 	// `Offset()` and `Limit()` are not honoured by `Count()`
 	Count() (uint64, error)
@@ -75,6 +156,56 @@ type Result interface {
 	// otherwise.
 	Exists() (bool, error)
 
+	// Sum stores the sum of the values of the given numeric column within the
+	// result set into dst, which must be a pointer. Sums of integer columns
+	// are exact when dst points to an integer. dst is set to its zero value, or
+	// to nil if it's a pointer to a pointer, if there are no values to add.
+	// `Offset()` and `Limit()` are not honoured by `Sum()`.
+	//
+	// Example:
+	//
+	//   var total int64
+	//   err := orders.Find(db.Cond{"status": "paid"}).Sum("amount", &total)
+	Sum(column string, dst interface{}) error
+
+	// Avg returns the average of the values of the given numeric column within
+	// the result set, or zero if there are no values. `Offset()` and `Limit()`
+	// are not honoured by `Avg()`.
+	Avg(column string) (float64, error)
+
+	// Min stores the smallest value of the given column within the result set
+	// into dst, see Sum. Any column that can be compared can be used, like
+	// text or timestamp columns.
+	//
+	// Example:
+	//
+	//   var first time.Time
+	//   err := orders.Find().Min("created_at", &first)
+	Min(column string, dst interface{}) error
+
+	// Max stores the largest value of the given column within the result set
+	// into dst, see Min.
+	Max(column string, dst interface{}) error
+
+	// Pluck fetches the values of a single column into the given pointer to
+	// slice. Order, pagination and distinct settings of the result set are
+	// honoured.
+	//
+	// Example:
+	//
+	//   var names []string
+	//   err = res.OrderBy("name").Pluck("name", &names)
+	Pluck(column string, sliceOfValues interface{}) error
+
+	// Distinct makes the result set return unique rows only. The given columns,
+	// if any, replace the ones set with `Select()`.
+	//
+	// Example:
+	//
+	//   var cities []string
+	//   err = res.Distinct().Pluck("city", &cities)
+	Distinct(columns ...interface{}) Result
+
 	// Next fetches the next result within the result set and dumps it into the
 	// given pointer to struct or pointer to map. You must call
 	// `Close()` after finishing using `Next()`.
@@ -88,12 +219,18 @@ type Result interface {
 	// given pointer to struct or pointer to map. The result set is automatically
 	// closed after picking the element, so there is no need to call Close()
 	// after using One().
+	//
+	// When the result set selects a single column the pointer can also point to
+	// a scalar, like an int, a string, a time.Time or a sql.Scanner. Use a
+	// pointer to []interface{} to fetch the columns by position.
 	One(ptrToStruct interface{}) error
 
 	// All fetches all results within the result set and dumps them into the
 	// given pointer to slice of maps or structs.  The result set is
 	// automatically closed, so there is no need to call Close() after
 	// using All().
+	//
+	// The slice can also hold scalars or []interface{} tuples, see One.
 	All(sliceOfStructs interface{}) error
 
 	// Paginate splits the results of the query into pages containing pageSize
@@ -175,6 +312,30 @@ type Result interface {
 	// TotalEntries returns the total number of matching items in the result set.
 	TotalEntries() (uint64, error)
 
+	// Explain asks the database to describe the execution plan of the query
+	// that would be used to fetch the result set. A nil ctx means the
+	// session's default context.
+	Explain(ctx context.Context, opts *ExplainOptions) (*QueryPlan, error)
+
+	// Chunk walks the result set in chunks of at most size items and passes
+	// each chunk to fn, so large result sets can be processed without keeping
+	// a query open. Chunks are paginated by opts.Key or, unless `OrderBy()` is
+	// set, by the primary key of the collection, in ascending order. Result
+	// sets with `OrderBy()` and no key are paginated in the given order
+	// instead. `Limit()` caps the total number of items that are processed,
+	// db.ErrInvalidChunkQuery is returned if `Offset()` is set or if
+	// `OrderBy()` is set along with opts.Key. Processing stops when ctx is
+	// canceled. A nil ctx means the session's default context.
+	//
+	// Example:
+	//
+	//   var batch []Order
+	//   err = res.Chunk(ctx, 1000, func(sess db.Session, _ interface{}) error {
+	//     // process batch
+	//     return nil
+	//   }, &db.ChunkOptions{Into: &batch})
+	Chunk(ctx context.Context, size uint, fn ChunkFunc, opts *ChunkOptions) error
+
 	// Close closes the result set and frees all locked resources.
 	Close() error
 }
@@ -211,4 +372,21 @@ func NewInsertResult(id interface{}) InsertResult {
 // ID represents a record ID
 type ID interface{}
 
+// MustAffect returns err if it's not nil and ErrNoRowsAffected if no rows
+// were affected, it's meant to wrap UpdateCount and DeleteCount calls that
+// must modify an item.
+//
+// Example:
+//
+//	err = db.MustAffect(res.UpdateCount(values))
+func MustAffect(affected uint64, err error) error {
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNoRowsAffected
+	}
+	return nil
+}
+
 var _ = driver.Valuer(&insertResult{})
@@ -45,15 +45,49 @@ type Session interface {
 	// database.
 	Collections() ([]Collection, error)
 
-	// Save creates or updates a record.
+	// Save creates or updates a record. Records with a version field are
+	// updated using optimistic locking, see Collection.UpdateReturning. A
+	// versioned record with its primary key set is always updated, so
+	// db.ErrStaleRecord is returned if it was deleted; use Insert to create
+	// versioned records with explicit keys.
+	//
+	// If record tracking is enabled, see Settings.SetRecordTracking, records
+	// that implement TrackedRecord and are fetched with Get, Result.One or
+	// Result.All are tracked by the session, when they're saved only the
+	// columns that changed are updated and nothing is written if no column
+	// changed.
 	Save(record Record) error
 
+	// Changed returns the names of the columns of a tracked record that were
+	// modified since the record was fetched or saved. All the columns of the
+	// record are returned if the record is not tracked.
+	Changed(record Record) []string
+
 	// Get retrieves a record that matches the given condition.
 	Get(record Record, cond interface{}) error
 
 	// Delete deletes a record.
 	Delete(record Record) error
 
+	// CreateAll creates all the records of the given slice within a single
+	// transaction. Validation and hooks run for every record and records are
+	// inserted using multi-row INSERT statements when the adapter supports
+	// them. If any record fails validation or its hooks a RecordErrors value
+	// is returned and nothing is persisted. All the records must be of the same
+	// type, db.ErrMixedRecordTypes is returned otherwise.
+	CreateAll(records interface{}) error
+
+	// SaveAll creates or updates all the records of the given slice within a
+	// single transaction, see CreateAll. New records are inserted in batches
+	// and existing records are updated in batches too, with a single UPDATE
+	// statement per batch, unless their store implements StoreUpdater, they're
+	// versioned or their collection has a composite primary key.
+	SaveAll(records interface{}) error
+
+	// DeleteAll deletes all the records of the given slice within a single
+	// transaction using batched IN conditions, see CreateAll.
+	DeleteAll(records interface{}) error
+
 	// Reset resets all the caching mechanisms the adapter is using.
 	Reset()
 
@@ -37,6 +37,24 @@ type Settings interface {
 	// is enabled, false otherwise.
 	PreparedStatementCacheEnabled() bool
 
+	// SetPreparedStatementCacheCapacity sets the maximum number of prepared
+	// statements a session keeps, older statements are closed and evicted
+	// when the capacity is exceeded. Values lower than one are ignored.
+	SetPreparedStatementCacheCapacity(int)
+
+	// PreparedStatementCacheCapacity returns the maximum number of prepared
+	// statements a session keeps.
+	PreparedStatementCacheCapacity() int
+
+	// SetPreparedStatementCacheMaxIdleTime sets the maximum amount of time a
+	// prepared statement may remain unused before being closed and evicted. A
+	// zero value means statements are only evicted because of capacity.
+	SetPreparedStatementCacheMaxIdleTime(time.Duration)
+
+	// PreparedStatementCacheMaxIdleTime returns the maximum amount of time a
+	// prepared statement may remain unused before being closed and evicted.
+	PreparedStatementCacheMaxIdleTime() time.Duration
+
 	// SetConnMaxLifetime sets the default maximum amount of time a connection
 	// may be reused.
 	SetConnMaxLifetime(time.Duration)
@@ -76,12 +94,87 @@ type Settings interface {
 	// MaxTransactionRetries returns the maximum number of times a
 	// transaction can be retried.
 	MaxTransactionRetries() int
+
+	// SetSlowQueryThreshold sets the minimum amount of time a query must take
+	// to be logged as a slow query. A zero threshold disables slow query
+	// detection.
+	SetSlowQueryThreshold(time.Duration)
+
+	// SlowQueryThreshold returns the minimum amount of time a query must take
+	// to be logged as a slow query.
+	SlowQueryThreshold() time.Duration
+
+	// SetQueryArgsLogging enables or disables logging query arguments.
+	SetQueryArgsLogging(bool)
+
+	// QueryArgsLoggingEnabled returns true if query arguments are included in
+	// query logs, false otherwise.
+	QueryArgsLoggingEnabled() bool
+
+	// SetQueryStackCapture enables or disables capturing the caller's stack
+	// when logging queries. Capturing the stack is expensive.
+	SetQueryStackCapture(bool)
+
+	// QueryStackCaptureEnabled returns true if the caller's stack is captured
+	// when logging queries, false otherwise.
+	QueryStackCaptureEnabled() bool
+
+	// SetQueryLogLevel sets the minimum level a query log entry must have to be
+	// sent to the logging collector. Successful queries are logged with
+	// LogLevelDebug, failed and slow queries are logged with LogLevelWarn.
+	SetQueryLogLevel(LogLevel)
+
+	// QueryLogLevel returns the minimum level a query log entry must have to be
+	// sent to the logging collector.
+	QueryLogLevel() LogLevel
+
+	// SetClock sets the function used to get the current time when setting
+	// automatic timestamps (autocreatetime and autoupdatetime) and soft delete
+	// columns. A nil clock means time.Now.
+	SetClock(func() time.Time)
+
+	// Clock returns the function used to get the current time.
+	Clock() func() time.Time
+
+	// SetStrictMapping enables or disables strict mapping. When enabled,
+	// fetching rows into structs fails with a *MappingError if the rows have
+	// columns that don't map to any field, or if fields are not part of the
+	// rows, unless they're tagged as optional:
+	//
+	//	Nickname string `db:"nickname,optional"`
+	SetStrictMapping(bool)
+
+	// StrictMappingEnabled returns true if strict mapping is enabled, false
+	// otherwise.
+	StrictMappingEnabled() bool
+
+	// SetRecordTracking enables or disables record tracking. When enabled,
+	// SQL sessions take a snapshot of the records they fetch and save, so Save
+	// only updates the columns that changed. Snapshots are kept by records that
+	// implement TrackedRecord, other records are updated in full.
+	SetRecordTracking(bool)
+
+	// RecordTrackingEnabled returns true if record tracking is enabled, false
+	// otherwise.
+	RecordTrackingEnabled() bool
+
+	// SetFieldMapping sets how SQL adapters map the fields of structs to
+	// columns. For instance, to map untagged fields to snake case columns:
+	//
+	//	sess.SetFieldMapping(db.FieldMapping{NameFunc: db.SnakeCase})
+	SetFieldMapping(FieldMapping)
+
+	// FieldMapping returns how SQL adapters map the fields of structs to
+	// columns.
+	FieldMapping() FieldMapping
 }
 
 type settings struct {
 	sync.RWMutex
 
-	preparedStatementCacheEnabled uint32
+	preparedStatementCacheEnabled     uint32
+	preparedStatementCacheCapacity    int
+	preparedStatementCacheMaxIdleTime time.Duration
 
 	connMaxLifetime time.Duration
 	connMaxIdleTime time.Duration
@@ -89,6 +182,18 @@ type settings struct {
 	maxIdleConns    int
 
 	maxTransactionRetries int
+
+	slowQueryThreshold       time.Duration
+	queryArgsLoggingEnabled  uint32
+	queryStackCaptureEnabled uint32
+	queryLogLevel            LogLevel
+
+	clock func() time.Time
+
+	strictMappingEnabled  uint32
+	recordTrackingEnabled uint32
+
+	fieldMapping FieldMapping
 }
 
 func (c *settings) binaryOption(opt *uint32) bool {
@@ -111,6 +216,33 @@ func (c *settings) PreparedStatementCacheEnabled() bool {
 	return c.binaryOption(&c.preparedStatementCacheEnabled)
 }
 
+func (c *settings) SetPreparedStatementCacheCapacity(n int) {
+	if n < 1 {
+		return
+	}
+	c.Lock()
+	c.preparedStatementCacheCapacity = n
+	c.Unlock()
+}
+
+func (c *settings) PreparedStatementCacheCapacity() int {
+	c.RLock()
+	defer c.RUnlock()
+	return c.preparedStatementCacheCapacity
+}
+
+func (c *settings) SetPreparedStatementCacheMaxIdleTime(t time.Duration) {
+	c.Lock()
+	c.preparedStatementCacheMaxIdleTime = t
+	c.Unlock()
+}
+
+func (c *settings) PreparedStatementCacheMaxIdleTime() time.Duration {
+	c.RLock()
+	defer c.RUnlock()
+	return c.preparedStatementCacheMaxIdleTime
+}
+
 func (c *settings) SetConnMaxLifetime(t time.Duration) {
 	c.Lock()
 	c.connMaxLifetime = t
@@ -174,27 +306,134 @@ func (c *settings) MaxOpenConns() int {
 	return c.maxOpenConns
 }
 
+func (c *settings) SetSlowQueryThreshold(t time.Duration) {
+	c.Lock()
+	c.slowQueryThreshold = t
+	c.Unlock()
+}
+
+func (c *settings) SlowQueryThreshold() time.Duration {
+	c.RLock()
+	defer c.RUnlock()
+	return c.slowQueryThreshold
+}
+
+func (c *settings) SetQueryArgsLogging(value bool) {
+	c.setBinaryOption(&c.queryArgsLoggingEnabled, value)
+}
+
+func (c *settings) QueryArgsLoggingEnabled() bool {
+	return c.binaryOption(&c.queryArgsLoggingEnabled)
+}
+
+func (c *settings) SetQueryStackCapture(value bool) {
+	c.setBinaryOption(&c.queryStackCaptureEnabled, value)
+}
+
+func (c *settings) QueryStackCaptureEnabled() bool {
+	return c.binaryOption(&c.queryStackCaptureEnabled)
+}
+
+func (c *settings) SetQueryLogLevel(level LogLevel) {
+	c.Lock()
+	c.queryLogLevel = level
+	c.Unlock()
+}
+
+func (c *settings) QueryLogLevel() LogLevel {
+	c.RLock()
+	defer c.RUnlock()
+	return c.queryLogLevel
+}
+
+func (c *settings) SetClock(clock func() time.Time) {
+	c.Lock()
+	c.clock = clock
+	c.Unlock()
+}
+
+func (c *settings) Clock() func() time.Time {
+	c.RLock()
+	defer c.RUnlock()
+	if c.clock == nil {
+		return time.Now
+	}
+	return c.clock
+}
+
+func (c *settings) SetStrictMapping(value bool) {
+	c.setBinaryOption(&c.strictMappingEnabled, value)
+}
+
+func (c *settings) StrictMappingEnabled() bool {
+	return c.binaryOption(&c.strictMappingEnabled)
+}
+
+func (c *settings) SetRecordTracking(value bool) {
+	c.setBinaryOption(&c.recordTrackingEnabled, value)
+}
+
+func (c *settings) RecordTrackingEnabled() bool {
+	return c.binaryOption(&c.recordTrackingEnabled)
+}
+
+func (c *settings) SetFieldMapping(mapping FieldMapping) {
+	c.Lock()
+	c.fieldMapping = mapping
+	c.Unlock()
+}
+
+func (c *settings) FieldMapping() FieldMapping {
+	c.RLock()
+	defer c.RUnlock()
+	mapping := c.fieldMapping
+	if mapping.TagName == "" {
+		mapping.TagName = "db"
+	}
+	return mapping
+}
+
 // NewSettings returns a new settings value prefilled with the current default
 // settings.
 func NewSettings() Settings {
 	def := DefaultSettings.(*settings)
 	return &settings{
-		preparedStatementCacheEnabled: def.preparedStatementCacheEnabled,
-		connMaxLifetime:               def.connMaxLifetime,
-		connMaxIdleTime:               def.connMaxIdleTime,
-		maxIdleConns:                  def.maxIdleConns,
-		maxOpenConns:                  def.maxOpenConns,
-		maxTransactionRetries:         def.maxTransactionRetries,
+		preparedStatementCacheEnabled:     def.preparedStatementCacheEnabled,
+		preparedStatementCacheCapacity:    def.preparedStatementCacheCapacity,
+		preparedStatementCacheMaxIdleTime: def.preparedStatementCacheMaxIdleTime,
+		connMaxLifetime:                   def.connMaxLifetime,
+		connMaxIdleTime:                   def.connMaxIdleTime,
+		maxIdleConns:                      def.maxIdleConns,
+		maxOpenConns:                      def.maxOpenConns,
+		maxTransactionRetries:             def.maxTransactionRetries,
+		slowQueryThreshold:                def.slowQueryThreshold,
+		queryArgsLoggingEnabled:           def.queryArgsLoggingEnabled,
+		queryStackCaptureEnabled:          def.queryStackCaptureEnabled,
+		queryLogLevel:                     def.queryLogLevel,
+		clock:                             def.clock,
+		strictMappingEnabled:              def.strictMappingEnabled,
+		recordTrackingEnabled:             def.recordTrackingEnabled,
+		fieldMapping:                      def.FieldMapping(),
 	}
 }
 
+// DefaultSlowQueryThreshold is the slow query threshold of DefaultSettings.
+// Adapters may use a different one unless DefaultSettings is changed.
+const DefaultSlowQueryThreshold = time.Millisecond * 200
+
 // DefaultSettings provides default global configuration settings for database
 // sessions.
 var DefaultSettings Settings = &settings{
-	preparedStatementCacheEnabled: 0,
-	connMaxLifetime:               time.Duration(0),
-	connMaxIdleTime:               time.Duration(0),
-	maxIdleConns:                  10,
-	maxOpenConns:                  0,
-	maxTransactionRetries:         1,
+	preparedStatementCacheEnabled:     0,
+	preparedStatementCacheCapacity:    128,
+	preparedStatementCacheMaxIdleTime: time.Duration(0),
+	connMaxLifetime:                   time.Duration(0),
+	connMaxIdleTime:                   time.Duration(0),
+	maxIdleConns:                      10,
+	maxOpenConns:                      0,
+	maxTransactionRetries:             1,
+	slowQueryThreshold:                DefaultSlowQueryThreshold,
+	queryArgsLoggingEnabled:           1,
+	queryStackCaptureEnabled:          1,
+	queryLogLevel:                     LogLevelDebug,
 }
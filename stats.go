@@ -0,0 +1,154 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DefaultLatencyBuckets defines the upper bounds of the latency histograms
+// built by sessions that collect query metrics.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	time.Millisecond * 5,
+	time.Millisecond * 10,
+	time.Millisecond * 25,
+	time.Millisecond * 50,
+	time.Millisecond * 100,
+	time.Millisecond * 250,
+	time.Millisecond * 500,
+	time.Second,
+	time.Millisecond * 2500,
+	time.Second * 5,
+	time.Second * 10,
+}
+
+// LatencyHistogram counts query durations into buckets.
+type LatencyHistogram struct {
+	// Bounds holds the upper bound of each bucket in ascending order.
+	Bounds []time.Duration
+
+	// Counts holds the number of observations that fell into each bucket, the
+	// last element counts observations greater than the last bound.
+	Counts []uint64
+
+	// Count is the total number of observations.
+	Count uint64
+
+	// Sum is the sum of all observed durations.
+	Sum time.Duration
+}
+
+// NewLatencyHistogram creates an empty histogram with the given bucket bounds.
+func NewLatencyHistogram(bounds []time.Duration) *LatencyHistogram {
+	return &LatencyHistogram{
+		Bounds: bounds,
+		Counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Observe adds the given duration to the histogram.
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	i := 0
+	for i < len(h.Bounds) && d > h.Bounds[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+}
+
+// QueryStats holds the metrics of all queries of the same statement type that
+// were sent to the same table.
+type QueryStats struct {
+	// Type is the statement type, like SELECT or UPDATE.
+	Type string
+
+	// Table is the name of the table the statement was sent to, it's empty for
+	// raw SQL queries.
+	Table string
+
+	// Count is the number of executed queries.
+	Count uint64
+
+	// Errors is the number of queries that returned an error.
+	Errors uint64
+
+	// Latency is the distribution of query durations.
+	Latency LatencyHistogram
+}
+
+// Stats is a snapshot of the metrics collected by a session.
+type Stats struct {
+	// Queries holds query metrics grouped by statement type and table.
+	Queries []QueryStats
+
+	// PreparedStatementCacheHits is the number of times a prepared statement
+	// was found in the cache.
+	PreparedStatementCacheHits uint64
+
+	// PreparedStatementCacheMisses is the number of times a prepared statement
+	// had to be created because it wasn't in the cache.
+	PreparedStatementCacheMisses uint64
+
+	// PreparedStatementCacheEvictions is the number of prepared statements
+	// that were closed and removed from the cache because of its capacity or
+	// because they remained unused for too long.
+	PreparedStatementCacheEvictions uint64
+
+	// DB holds the connection pool statistics of the underlying *sql.DB.
+	DB sql.DBStats
+}
+
+// QueryMetric describes a single executed query.
+type QueryMetric struct {
+	Type     string
+	Table    string
+	Duration time.Duration
+	Err      error
+}
+
+// StatsCollector receives metrics as soon as a session records them. It can be
+// used to bridge session metrics into external monitoring systems.
+type StatsCollector interface {
+	// CollectQuery is called after a query is executed.
+	CollectQuery(QueryMetric)
+
+	// CollectStatementCache is called after looking up a prepared statement in
+	// the cache, hit is true if the statement was found.
+	CollectStatementCache(hit bool)
+
+	// CollectStatementCacheEviction is called after a prepared statement is
+	// evicted from the cache.
+	CollectStatementCacheEviction()
+}
+
+// StatsReporter is implemented by sessions that collect query metrics.
+type StatsReporter interface {
+	// Stats returns a snapshot of the metrics collected by the session.
+	Stats() Stats
+
+	// SetStatsCollector sets a collector that receives metrics as soon as
+	// they're recorded by the session. Use nil to remove it.
+	SetStatsCollector(StatsCollector)
+}
@@ -1,7 +1,9 @@
 package db_test
 
 import (
+	"context"
 	"database/sql/driver"
+	"errors"
 	"time"
 
 	"github.com/stretchr/testify/suite"
@@ -890,3 +892,432 @@ func (s *GenericTestSuite) TestComparisonOperators() {
 		s.Equal(3, len(items))
 	}
 }
+
+func (s *GenericTestSuite) TestFetchScalarsAndTuples() {
+	sess := s.Session()
+
+	col := sess.Collection("fibonacci")
+
+	var i uint64
+	for i = 0; i < 10; i++ {
+		_, err := col.Insert(fibonacci{Input: i, Output: fib(i)})
+		s.Require().NoError(err)
+	}
+
+	res := col.Find(db.Cond{"input <": 6}).OrderBy("input")
+
+	{
+		var outputs []int64
+		err := res.Select("output").All(&outputs)
+		s.Require().NoError(err)
+		s.Equal([]int64{0, 1, 1, 2, 3, 5}, outputs)
+	}
+
+	{
+		var output int
+		err := res.Select("output").OrderBy("-input").One(&output)
+		s.Require().NoError(err)
+		s.Equal(5, output)
+
+		var outputPtr *uint64
+		err = res.Select("output").OrderBy("-input").One(&outputPtr)
+		s.Require().NoError(err)
+		s.Require().NotNil(outputPtr)
+		s.Equal(uint64(5), *outputPtr)
+	}
+
+	{
+		var rows [][]interface{}
+		err := res.Select("input", "output").All(&rows)
+		s.Require().NoError(err)
+		s.Require().Len(rows, 6)
+		for _, row := range rows {
+			s.Len(row, 2)
+		}
+	}
+
+	{
+		var outputs []int64
+		err := res.Select("input", "output").All(&outputs)
+		s.Error(err)
+	}
+}
+
+func (s *GenericTestSuite) TestStrictMapping() {
+	sess := s.Session()
+
+	col := sess.Collection("fibonacci")
+
+	var i uint64
+	for i = 0; i < 5; i++ {
+		_, err := col.Insert(fibonacci{Input: i, Output: fib(i)})
+		s.Require().NoError(err)
+	}
+
+	type inputOnly struct {
+		Input uint64 `db:"input" bson:"input"`
+	}
+
+	type inputOutput struct {
+		Input  uint64 `db:"input" bson:"input"`
+		Output uint64 `db:"output" bson:"output"`
+	}
+
+	type optionalOutput struct {
+		Input  uint64 `db:"input" bson:"input"`
+		Output uint64 `db:"output,optional" bson:"output"`
+	}
+
+	res := col.Find().OrderBy("input")
+
+	{
+		var items []inputOnly
+		err := res.Select("input", "output").All(&items)
+		s.Require().NoError(err)
+		s.Len(items, 5)
+	}
+
+	{
+		var items []inputOnly
+		err := res.Select("input", "output").StrictMapping(true).All(&items)
+
+		var mappingErr *db.MappingError
+		s.Require().ErrorAs(err, &mappingErr)
+		s.Equal([]string{"output"}, mappingErr.UnmappedColumns)
+		s.Empty(mappingErr.MissingFields)
+	}
+
+	{
+		var item inputOutput
+		err := res.Select("input").StrictMapping(true).One(&item)
+
+		var mappingErr *db.MappingError
+		s.Require().ErrorAs(err, &mappingErr)
+		s.Empty(mappingErr.UnmappedColumns)
+		s.Equal([]string{"output"}, mappingErr.MissingFields)
+	}
+
+	{
+		var items []optionalOutput
+		err := res.Select("input").StrictMapping(true).All(&items)
+		s.Require().NoError(err)
+		s.Len(items, 5)
+	}
+
+	sess.SetStrictMapping(true)
+	defer sess.SetStrictMapping(false)
+
+	{
+		var items []inputOutput
+		err := res.Select("input", "output").All(&items)
+		s.Require().NoError(err)
+		s.Len(items, 5)
+
+		var item inputOnly
+		err = res.Select("input", "output").One(&item)
+		s.Require().ErrorAs(err, new(*db.MappingError))
+
+		err = res.Select("input", "output").StrictMapping(false).One(&item)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *GenericTestSuite) TestAggregates() {
+	sess := s.Session()
+
+	col := sess.Collection("fibonacci")
+
+	var i uint64
+	for i = 0; i < 10; i++ {
+		_, err := col.Insert(fibonacci{Input: i, Output: fib(i)})
+		s.Require().NoError(err)
+	}
+
+	res := col.Find(db.Cond{"input <": 6})
+
+	{
+		var sum int64
+		err := res.Sum("output", &sum)
+		s.Require().NoError(err)
+		s.Equal(int64(12), sum)
+
+		var fsum float64
+		err = res.Sum("output", &fsum)
+		s.Require().NoError(err)
+		s.Equal(float64(12), fsum)
+
+		avg, err := res.Avg("output")
+		s.Require().NoError(err)
+		s.Equal(float64(2), avg)
+
+		var lowest int64
+		err = res.Min("output", &lowest)
+		s.Require().NoError(err)
+		s.Equal(int64(0), lowest)
+
+		var highest uint64
+		err = res.Max("output", &highest)
+		s.Require().NoError(err)
+		s.Equal(uint64(5), highest)
+
+		err = res.Max("output", highest)
+		s.Error(err)
+	}
+
+	{
+		sum := int64(7)
+		err := col.Find(db.Cond{"input >": 100}).Sum("output", &sum)
+		s.Require().NoError(err)
+		s.Zero(sum)
+
+		highest := new(int64)
+		err = col.Find(db.Cond{"input >": 100}).Max("output", &highest)
+		s.Require().NoError(err)
+		s.Nil(highest)
+	}
+
+	{
+		var outputs []int64
+		err := res.OrderBy("-input").Pluck("output", &outputs)
+		s.Require().NoError(err)
+		s.Equal([]int64{5, 3, 2, 1, 1, 0}, outputs)
+	}
+
+	{
+		var outputs []int64
+		err := res.Distinct().OrderBy("output").Pluck("output", &outputs)
+		s.Require().NoError(err)
+		s.Equal([]int64{0, 1, 2, 3, 5}, outputs)
+
+		count, err := res.Distinct("output").Count()
+		s.Require().NoError(err)
+		s.Equal(uint64(5), count)
+
+		var sum int64
+		err = res.Distinct("output").Sum("output", &sum)
+		s.Require().NoError(err)
+		s.Equal(int64(11), sum)
+	}
+}
+
+func (s *GenericTestSuite) TestChunk() {
+	sess := s.Session()
+
+	col := sess.Collection("fibonacci")
+
+	var i uint64
+	for i = 0; i < 10; i++ {
+		_, err := col.Insert(fibonacci{Input: i, Output: fib(i)})
+		s.Require().NoError(err)
+	}
+
+	res := col.Find(db.Cond{"input <": 8})
+
+	{
+		var sizes []int
+		err := res.Chunk(nil, 3, func(sess db.Session, batch interface{}) error {
+			s.NotNil(sess)
+			sizes = append(sizes, len(*batch.(*[]map[string]interface{})))
+			return nil
+		}, nil)
+		s.Require().NoError(err)
+		s.Equal([]int{3, 3, 2}, sizes)
+	}
+
+	{
+		var batch []fibonacci
+		var inputs []uint64
+		err := res.Chunk(context.Background(), 4, func(db.Session, interface{}) error {
+			for _, item := range batch {
+				inputs = append(inputs, item.Input)
+			}
+			return nil
+		}, &db.ChunkOptions{Into: &batch, Key: "input"})
+		s.Require().NoError(err)
+		s.Equal([]uint64{0, 1, 2, 3, 4, 5, 6, 7}, inputs)
+
+		err = res.OrderBy("-input").Chunk(nil, 4, func(db.Session, interface{}) error {
+			return nil
+		}, &db.ChunkOptions{Into: &batch, Key: "input"})
+		s.ErrorIs(err, db.ErrInvalidChunkQuery)
+
+		err = res.Offset(2).Chunk(nil, 4, func(db.Session, interface{}) error {
+			return nil
+		}, &db.ChunkOptions{Into: &batch})
+		s.ErrorIs(err, db.ErrInvalidChunkQuery)
+	}
+
+	{
+		// Ordered result sets are walked in their own order.
+		var batch []fibonacci
+		var inputs []uint64
+		err := res.OrderBy("-input").Chunk(nil, 3, func(db.Session, interface{}) error {
+			for _, item := range batch {
+				inputs = append(inputs, item.Input)
+			}
+			return nil
+		}, &db.ChunkOptions{Into: &batch})
+		s.Require().NoError(err)
+		s.Equal([]uint64{7, 6, 5, 4, 3, 2, 1, 0}, inputs)
+	}
+
+	{
+		// The limit caps the number of items that are processed.
+		var batch []fibonacci
+		var sizes []int
+		var inputs []uint64
+		err := res.Limit(5).Chunk(nil, 2, func(db.Session, interface{}) error {
+			sizes = append(sizes, len(batch))
+			for _, item := range batch {
+				inputs = append(inputs, item.Input)
+			}
+			return nil
+		}, &db.ChunkOptions{Into: &batch, Key: "input"})
+		s.Require().NoError(err)
+		s.Equal([]int{2, 2, 1}, sizes)
+		s.Equal([]uint64{0, 1, 2, 3, 4}, inputs)
+
+		sizes = nil
+		err = res.OrderBy("input").Limit(5).Chunk(nil, 2, func(db.Session, interface{}) error {
+			sizes = append(sizes, len(batch))
+			return nil
+		}, &db.ChunkOptions{Into: &batch})
+		s.Require().NoError(err)
+		s.Equal([]int{2, 2, 1}, sizes)
+	}
+
+	{
+		errStop := errors.New("stop")
+
+		calls := 0
+		err := res.Chunk(nil, 2, func(db.Session, interface{}) error {
+			calls++
+			return errStop
+		}, nil)
+		s.ErrorIs(err, errStop)
+		s.Equal(1, calls)
+	}
+
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := res.Chunk(ctx, 2, func(db.Session, interface{}) error {
+			return nil
+		}, nil)
+		s.ErrorIs(err, context.Canceled)
+
+		err = res.Chunk(nil, 0, func(db.Session, interface{}) error {
+			return nil
+		}, nil)
+		s.ErrorIs(err, db.ErrInvalidChunkSize)
+	}
+}
+
+func (s *GenericTestSuite) TestAffectedCount() {
+	sess := s.Session()
+
+	col := sess.Collection("fibonacci")
+
+	var i uint64
+	for i = 0; i < 10; i++ {
+		_, err := col.Insert(fibonacci{Input: i, Output: fib(i)})
+		s.Require().NoError(err)
+	}
+
+	{
+		updated, err := col.Find(db.Cond{"input <": 3}).UpdateCount(map[string]interface{}{"output": 100})
+		s.Require().NoError(err)
+		s.Equal(uint64(3), updated)
+
+		updated, err = col.Find(db.Cond{"input >": 100}).UpdateCount(map[string]interface{}{"output": 100})
+		s.Require().NoError(err)
+		s.Equal(uint64(0), updated)
+
+		err = db.MustAffect(col.Find(db.Cond{"input": 100}).UpdateCount(map[string]interface{}{"output": 100}))
+		s.ErrorIs(err, db.ErrNoRowsAffected)
+	}
+
+	{
+		deleted, err := col.Find(db.Cond{"input >=": 7}).DeleteCount()
+		s.Require().NoError(err)
+		s.Equal(uint64(3), deleted)
+
+		deleted, err = col.Find(db.Cond{"input >=": 7}).HardDeleteCount()
+		s.Require().NoError(err)
+		s.Equal(uint64(0), deleted)
+
+		err = db.MustAffect(col.Find(db.Cond{"input": 5}).DeleteCount())
+		s.Require().NoError(err)
+
+		count, err := col.Find().Count()
+		s.Require().NoError(err)
+		s.Equal(uint64(6), count)
+	}
+}
+
+func (s *GenericTestSuite) TestInsertMany() {
+	sess := s.Session()
+
+	col := sess.Collection("fibonacci")
+
+	items := make([]fibonacci, 1200)
+	for i := range items {
+		items[i] = fibonacci{Input: uint64(i), Output: uint64(i) * 2}
+	}
+
+	ids, err := col.InsertMany(items)
+	s.Require().NoError(err)
+	s.Require().Len(ids, len(items))
+
+	for _, i := range []int{0, 499, 500, 1199} {
+		var item fibonacci
+		err := col.Find(ids[i]).One(&item)
+		s.Require().NoError(err)
+		s.Equal(items[i], item)
+	}
+
+	count, err := col.Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(len(items)), count)
+
+	ids, err = col.InsertMany([]fibonacci{})
+	s.Require().NoError(err)
+	s.Empty(ids)
+
+	_, err = col.InsertMany(fibonacci{})
+	s.Error(err)
+}
+
+func (s *GenericTestSuite) TestAtomicUpdates() {
+	sess := s.Session()
+
+	col := sess.Collection("fibonacci")
+
+	var i uint64
+	for i = 0; i < 5; i++ {
+		_, err := col.Insert(fibonacci{Input: i, Output: fib(i)})
+		s.Require().NoError(err)
+	}
+
+	outputs := func() []int64 {
+		var outputs []int64
+		err := col.Find().OrderBy("input").Pluck("output", &outputs)
+		s.Require().NoError(err)
+		return outputs
+	}
+
+	err := col.Find(db.Cond{"input >=": 3}).Increment("output", 10)
+	s.Require().NoError(err)
+	s.Equal([]int64{0, 1, 1, 12, 13}, outputs())
+
+	err = col.Find(db.Cond{"input": 4}).Decrement("output", 3)
+	s.Require().NoError(err)
+	s.Equal([]int64{0, 1, 1, 12, 10}, outputs())
+
+	err = col.Find(db.Cond{"input >=": 3}).Update(map[string]interface{}{
+		"output": db.Column("input"),
+	})
+	s.Require().NoError(err)
+	s.Equal([]int64{0, 1, 1, 3, 4}, outputs())
+}
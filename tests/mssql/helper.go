@@ -131,7 +131,10 @@ func (h *Helper) SetUp() error {
 			id BIGINT PRIMARY KEY NOT NULL IDENTITY(1,1),
 			name nvarchar(255),
 			disabled tinyint,
-			created_at DATETIME NOT NULL DEFAULT(GETDATE())
+			created_at DATETIME NOT NULL DEFAULT(GETDATE()),
+			deleted_at DATETIME NULL,
+			version INT NOT NULL DEFAULT(0),
+			updated_at DATETIME NULL
 		)`,
 
 		`DROP TABLE IF EXISTS [users]`,
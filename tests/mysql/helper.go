@@ -229,7 +229,10 @@ func (h *Helper) SetUp() error {
 			PRIMARY KEY(id),
 			name varchar(255),
 			disabled BOOL,
-			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP NULL,
+			version INT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP NULL
 		)`,
 
 		`DROP TABLE IF EXISTS users`,
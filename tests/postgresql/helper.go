@@ -266,7 +266,10 @@ func (h *Helper) SetUp() error {
 			id serial primary key,
 			name varchar(255),
 			disabled boolean,
-			created_at timestamp with time zone
+			created_at timestamp with time zone,
+			deleted_at timestamp with time zone,
+			version integer NOT NULL DEFAULT 0,
+			updated_at timestamp with time zone
 		)`,
 
 		`DROP TABLE IF EXISTS users`,
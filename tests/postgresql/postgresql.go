@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
@@ -1401,6 +1402,90 @@ func (s *AdapterTests) Test_Issue601_ErrorCarrying() {
 	s.NoError(err)
 }
 
+func (s *AdapterTests) TestCopyFrom() {
+	type artistType struct {
+		ID   int64  `db:"id,omitempty"`
+		Name string `db:"name"`
+	}
+
+	sess := s.Session()
+
+	err := sess.Collection("artist").Truncate()
+	s.Require().NoError(err)
+
+	{
+		artists := make([]artistType, 1000)
+		for i := range artists {
+			artists[i] = artistType{Name: fmt.Sprintf("Artist %d", i)}
+		}
+
+		n, err := postgresql.CopyFrom(sess, "artist", artists)
+		s.Require().NoError(err)
+		s.Equal(int64(1000), n)
+	}
+
+	{
+		rows := make(chan map[string]interface{})
+		go func() {
+			defer close(rows)
+			for i := 0; i < 10; i++ {
+				rows <- map[string]interface{}{"name": fmt.Sprintf("Channel %d", i)}
+			}
+		}()
+
+		n, err := postgresql.CopyFrom(sess, "public.artist", rows)
+		s.Require().NoError(err)
+		s.Equal(int64(10), n)
+	}
+
+	{
+		n, err := postgresql.CopyFrom(sess, "artist", [][]interface{}{{"Tuple"}}, "name")
+		s.Require().NoError(err)
+		s.Equal(int64(1), n)
+
+		_, err = postgresql.CopyFrom(sess, "artist", [][]interface{}{{"Tuple"}})
+		s.Error(err)
+
+		n, err = postgresql.CopyFrom(sess, "artist", []artistType{})
+		s.Require().NoError(err)
+		s.Equal(int64(0), n)
+	}
+
+	{
+		err := sess.TxContext(context.Background(), func(tx db.Session) error {
+			n, err := postgresql.CopyFrom(tx, "artist", []artistType{{Name: "Rolled back"}})
+			s.Require().NoError(err)
+			s.Equal(int64(1), n)
+
+			count, err := tx.Collection("artist").Find(db.Cond{"name": "Rolled back"}).Count()
+			s.Require().NoError(err)
+			s.Equal(uint64(1), count)
+
+			return errors.New("rollback")
+		}, nil)
+		s.Error(err)
+
+		count, err := sess.Collection("artist").Find(db.Cond{"name": "Rolled back"}).Count()
+		s.Require().NoError(err)
+		s.Equal(uint64(0), count)
+	}
+
+	{
+		iter := sess.SQL().Select("name").From("artist").Where("name LIKE ?", "Channel%").Iterator()
+
+		n, err := postgresql.CopyFrom(sess, "artist", iter)
+		s.Require().NoError(err)
+		s.Equal(int64(10), n)
+	}
+
+	count, err := sess.Collection("artist").Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(1021), count)
+
+	_, err = postgresql.CopyFrom(sess, "artist", []map[string]interface{}{{"unknown": 1}})
+	s.Error(err)
+}
+
 func TestAdapter(t *testing.T) {
 	suite.Run(t, &AdapterTests{})
 }
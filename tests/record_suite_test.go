@@ -3,6 +3,7 @@ package db_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -62,6 +63,192 @@ func (account *Account) AfterCreate(sess db.Session) error {
 	return sess.Save(&Log{Message: message})
 }
 
+type DeletableAccount struct {
+	ID        uint64     `db:"id,omitempty"`
+	Name      string     `db:"name"`
+	DeletedAt *time.Time `db:"deleted_at,omitempty"`
+}
+
+func (*DeletableAccount) Store(sess db.Session) db.Store {
+	return DeletableAccounts(sess)
+}
+
+func DeletableAccounts(sess db.Session) db.Store {
+	return &AccountsStore{sess.Collection("accounts").SoftDelete("deleted_at")}
+}
+
+type VersionedAccount struct {
+	ID      uint64 `db:"id,omitempty"`
+	Name    string `db:"name"`
+	Version uint64 `db:"version,version"`
+}
+
+func (*VersionedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type TimestampedAccount struct {
+	ID        uint64     `db:"id,omitempty"`
+	Name      string     `db:"name"`
+	CreatedAt time.Time  `db:"created_at,autocreatetime"`
+	UpdatedAt *time.Time `db:"updated_at,autoupdatetime,omitempty"`
+}
+
+func (*TimestampedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type ValidatedAccount struct {
+	ID   uint64 `db:"id,omitempty"`
+	Name string `db:"name"`
+}
+
+func (*ValidatedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+func (account *ValidatedAccount) Validate() error {
+	if account.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type RuledAccount struct {
+	ID   uint64 `db:"id,omitempty"`
+	Name string `db:"name,required,maxlen=16"`
+}
+
+func (*RuledAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type ScopedAccount struct {
+	ID       uint64 `db:"id,omitempty"`
+	Name     string `db:"name"`
+	Disabled bool   `db:"disabled"`
+}
+
+func (*ScopedAccount) Store(sess db.Session) db.Store {
+	return DisabledAccounts(sess)
+}
+
+func DisabledAccounts(sess db.Session) db.Store {
+	return &AccountsStore{sess.Collection("accounts").Scope(db.Cond{"disabled": true})}
+}
+
+type TrackedAccount struct {
+	db.Tracked
+
+	ID        uint64     `db:"id,omitempty"`
+	Name      string     `db:"name"`
+	Disabled  bool       `db:"disabled"`
+	CreatedAt *time.Time `db:"created_at,omitempty"`
+}
+
+func (*TrackedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type EncryptedAccount struct {
+	db.Tracked
+
+	ID   uint64 `db:"id,omitempty"`
+	Name string `db:"name,codec=aes"`
+}
+
+func (*EncryptedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+type hookContextKey struct{}
+
+type HookedAccount struct {
+	ID   uint64 `db:"id,omitempty"`
+	Name string `db:"name"`
+
+	Hooks []string `db:"-"`
+}
+
+func (*HookedAccount) Store(sess db.Session) db.Store {
+	return Accounts(sess)
+}
+
+func (account *HookedAccount) hook(ctx context.Context, name string) error {
+	account.Hooks = append(account.Hooks, fmt.Sprintf("%s:%v", name, ctx.Value(hookContextKey{})))
+	return nil
+}
+
+func (account *HookedAccount) BeforeSaveContext(ctx context.Context, sess db.Session) error {
+	return account.hook(ctx, "BeforeSave")
+}
+
+func (account *HookedAccount) BeforeCreateContext(ctx context.Context, sess db.Session) error {
+	return account.hook(ctx, "BeforeCreate")
+}
+
+func (account *HookedAccount) AfterCreateContext(ctx context.Context, sess db.Session) error {
+	return account.hook(ctx, "AfterCreate")
+}
+
+func (account *HookedAccount) BeforeUpdateContext(ctx context.Context, sess db.Session) error {
+	return account.hook(ctx, "BeforeUpdate")
+}
+
+func (account *HookedAccount) AfterUpdateContext(ctx context.Context, sess db.Session) error {
+	return account.hook(ctx, "AfterUpdate")
+}
+
+func (account *HookedAccount) AfterSave(sess db.Session) error {
+	return account.hook(sess.Context(), "AfterSave")
+}
+
+func (account *HookedAccount) AfterFindContext(ctx context.Context, sess db.Session) error {
+	return account.hook(ctx, "AfterFind")
+}
+
+func (account *HookedAccount) AfterFind(sess db.Session) error {
+	return errors.New("AfterFindContext is preferred")
+}
+
+type AccountWithUsers struct {
+	ID    uint64         `db:"id,omitempty"`
+	Name  string         `db:"name"`
+	Users []*UserAccount `db:"-"`
+}
+
+func (*AccountWithUsers) Relations() map[string]db.Relation {
+	return map[string]db.Relation{
+		"Users": {Kind: db.HasMany, Collection: "users", ForeignKey: "account_id"},
+	}
+}
+
+type UserAccount struct {
+	ID        uint64            `db:"id,omitempty"`
+	AccountID uint64            `db:"account_id"`
+	Username  string            `db:"username"`
+	Account   *AccountWithUsers `db:"-"`
+}
+
+func (*UserAccount) Relations() map[string]db.Relation {
+	return map[string]db.Relation{
+		"Account": {Kind: db.BelongsTo, Collection: "accounts", ForeignKey: "account_id"},
+	}
+}
+
+type UserDeletableAccount struct {
+	ID        uint64            `db:"id,omitempty"`
+	AccountID uint64            `db:"account_id"`
+	Username  string            `db:"username"`
+	Account   *DeletableAccount `db:"-"`
+}
+
+func (*UserDeletableAccount) Relations() map[string]db.Relation {
+	return map[string]db.Relation{
+		"Account": {Kind: db.BelongsTo, Collection: "accounts", ForeignKey: "account_id"},
+	}
+}
+
 type User struct {
 	ID        uint64 `db:"id,omitempty"`
 	AccountID uint64 `db:"account_id"`
@@ -213,6 +400,557 @@ func (s *RecordTestSuite) TestDelete() {
 	s.Require().NoError(err)
 }
 
+func (s *RecordTestSuite) TestSoftDelete() {
+	sess := s.Session()
+
+	pressly := DeletableAccount{Name: "Pressly"}
+	err := sess.Save(&pressly)
+	s.Require().NoError(err)
+
+	upper := DeletableAccount{Name: "Upper"}
+	err = sess.Save(&upper)
+	s.Require().NoError(err)
+
+	_, ok := DeletableAccounts(sess).(db.SoftDeleter)
+	s.True(ok)
+
+	// Delete sets the soft delete column instead of removing the row.
+	err = sess.Delete(&pressly)
+	s.Require().NoError(err)
+	s.NotNil(pressly.DeletedAt)
+
+	count, err := DeletableAccounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(1), count)
+
+	count, err = DeletableAccounts(sess).Find().WithDeleted().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(2), count)
+
+	var deleted []DeletableAccount
+	err = DeletableAccounts(sess).Find().OnlyDeleted().All(&deleted)
+	s.Require().NoError(err)
+	s.Require().Len(deleted, 1)
+	s.Equal(pressly.ID, deleted[0].ID)
+	s.NotNil(deleted[0].DeletedAt)
+
+	err = sess.Get(&DeletableAccount{}, pressly.ID)
+	s.ErrorIs(err, db.ErrNoMoreRows)
+
+	// Saving a soft deleted record updates it.
+	pressly.Name = "Pressly Inc."
+	err = sess.Save(&pressly)
+	s.Require().NoError(err)
+
+	count, err = DeletableAccounts(sess).Find().WithDeleted().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(2), count)
+
+	// Delete on a soft delete result set.
+	err = DeletableAccounts(sess).Find(upper.ID).Delete()
+	s.Require().NoError(err)
+
+	// Items that were already soft deleted are not counted again.
+	deletedCount, err := DeletableAccounts(sess).Find(upper.ID).WithDeleted().DeleteCount()
+	s.Require().NoError(err)
+	s.Equal(uint64(0), deletedCount)
+
+	count, err = DeletableAccounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	count, err = Accounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(2), count)
+
+	// HardDelete removes the row.
+	err = DeletableAccounts(sess).Find(upper.ID).WithDeleted().HardDelete()
+	s.Require().NoError(err)
+
+	count, err = DeletableAccounts(sess).Find().OnlyDeleted().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(1), count)
+
+	// OnlyDeleted requires a soft delete column.
+	_, err = Accounts(sess).Find().OnlyDeleted().Count()
+	s.ErrorIs(err, db.ErrMissingSoftDeleteColumn)
+}
+
+func (s *RecordTestSuite) TestOptimisticLocking() {
+	sess := s.Session()
+
+	account := VersionedAccount{Name: "Pressly"}
+	err := sess.Save(&account)
+	s.Require().NoError(err)
+	s.Zero(account.Version)
+
+	var first, second VersionedAccount
+	s.Require().NoError(sess.Get(&first, account.ID))
+	s.Require().NoError(sess.Get(&second, account.ID))
+
+	first.Name = "Pressly Inc."
+	err = sess.Save(&first)
+	s.Require().NoError(err)
+	s.Equal(uint64(1), first.Version)
+
+	// second was read before first was saved.
+	second.Name = "Pressly LLC"
+	err = sess.Save(&second)
+	s.ErrorIs(err, db.ErrStaleRecord)
+	s.Zero(second.Version)
+
+	var stored VersionedAccount
+	s.Require().NoError(sess.Get(&stored, account.ID))
+	s.Equal("Pressly Inc.", stored.Name)
+	s.Equal(uint64(1), stored.Version)
+
+	// Reloading the record makes it fresh again.
+	s.Require().NoError(sess.Get(&second, account.ID))
+	second.Name = "Pressly LLC"
+	err = sess.Save(&second)
+	s.Require().NoError(err)
+	s.Equal(uint64(2), second.Version)
+
+	// A record that was deleted by someone else is stale, not new.
+	err = Accounts(sess).Find(account.ID).Delete()
+	s.Require().NoError(err)
+
+	second.Name = "Pressly Ltd."
+	err = sess.Save(&second)
+	s.ErrorIs(err, db.ErrStaleRecord)
+
+	count, err := Accounts(sess).Find(account.ID).Count()
+	s.Require().NoError(err)
+	s.Zero(count)
+}
+
+func (s *RecordTestSuite) TestAutoTimestamps() {
+	sess := s.Session()
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	sess.SetClock(func() time.Time { return now })
+	defer sess.SetClock(nil)
+
+	account := TimestampedAccount{Name: "Pressly"}
+	err := sess.Save(&account)
+	s.Require().NoError(err)
+	s.True(now.Equal(account.CreatedAt))
+	s.Require().NotNil(account.UpdatedAt)
+	s.True(now.Equal(*account.UpdatedAt))
+
+	createdAt := now
+	now = now.Add(time.Hour)
+
+	account.Name = "Pressly Inc."
+	err = sess.Save(&account)
+	s.Require().NoError(err)
+	s.True(createdAt.Equal(account.CreatedAt))
+	s.True(now.Equal(*account.UpdatedAt))
+
+	now = now.Add(time.Hour)
+
+	err = Accounts(sess).Find(account.ID).Update(TimestampedAccount{
+		Name:      "Pressly LLC",
+		CreatedAt: createdAt,
+	})
+	s.Require().NoError(err)
+
+	var stored TimestampedAccount
+	err = sess.Get(&stored, account.ID)
+	s.Require().NoError(err)
+	s.Equal("Pressly LLC", stored.Name)
+	s.True(createdAt.Equal(stored.CreatedAt))
+	s.Require().NotNil(stored.UpdatedAt)
+	s.True(now.Equal(*stored.UpdatedAt))
+}
+
+func (s *RecordTestSuite) TestPreload() {
+	sess := s.Session()
+
+	accounts := []*Account{{Name: "Pressly"}, {Name: "Upper"}, {Name: "Empty"}}
+	for _, account := range accounts {
+		s.Require().NoError(sess.Save(account))
+	}
+
+	usernames := map[uint64][]string{
+		accounts[0].ID: {"jose", "xiaio"},
+		accounts[1].ID: {"peter"},
+	}
+	for accountID, names := range usernames {
+		for _, name := range names {
+			s.Require().NoError(sess.Save(&User{AccountID: accountID, Username: name}))
+		}
+	}
+
+	var withUsers []AccountWithUsers
+	err := Accounts(sess).Find().OrderBy("id").Preload("Users").All(&withUsers)
+	s.Require().NoError(err)
+	s.Require().Len(withUsers, 3)
+	s.Len(withUsers[0].Users, 2)
+	s.Len(withUsers[1].Users, 1)
+	s.Equal("peter", withUsers[1].Users[0].Username)
+	s.NotNil(withUsers[2].Users)
+	s.Len(withUsers[2].Users, 0)
+
+	var users []UserAccount
+	err = Users(sess).Find().OrderBy("id").Preload("Account.Users").All(&users)
+	s.Require().NoError(err)
+	s.Require().Len(users, 3)
+	for _, user := range users {
+		s.Require().NotNil(user.Account)
+		s.Equal(user.AccountID, user.Account.ID)
+		s.Len(user.Account.Users, len(usernames[user.AccountID]))
+	}
+
+	var user UserAccount
+	err = Users(sess).Find(db.Cond{"username": "peter"}).Preload("Account").One(&user)
+	s.Require().NoError(err)
+	s.Require().NotNil(user.Account)
+	s.Equal("Upper", user.Account.Name)
+
+	err = Users(sess).Find().Preload("Unknown").All(&users)
+	s.ErrorIs(err, db.ErrUnknownRelation)
+
+	// Related items are loaded through their store, soft deleted ones are
+	// left out.
+	err = sess.Delete(&DeletableAccount{ID: accounts[1].ID})
+	s.Require().NoError(err)
+
+	var withDeleted UserDeletableAccount
+	err = Users(sess).Find(db.Cond{"username": "peter"}).Preload("Account").One(&withDeleted)
+	s.Require().NoError(err)
+	s.Nil(withDeleted.Account)
+}
+
+func (s *RecordTestSuite) TestTagValidation() {
+	sess := s.Session()
+
+	account := RuledAccount{Name: "Pressly"}
+	err := sess.Save(&account)
+	s.Require().NoError(err)
+
+	account.Name = ""
+	err = sess.Save(&account)
+
+	var validationErr *db.ValidationError
+	s.Require().True(errors.As(err, &validationErr))
+	s.Require().Len(validationErr.Field("name"), 1)
+	s.Equal("required", validationErr.Field("name")[0].Rule)
+
+	err = sess.Save(&RuledAccount{Name: "A name that is way too long"})
+	s.Require().True(errors.As(err, &validationErr))
+	s.Equal("maxlen", validationErr.Fields[0].Rule)
+
+	// Nothing but the valid account was persisted.
+	var accounts []Account
+	err = Accounts(sess).Find().All(&accounts)
+	s.Require().NoError(err)
+	s.Require().Len(accounts, 1)
+	s.Equal("Pressly", accounts[0].Name)
+}
+
+func (s *RecordTestSuite) TestScopes() {
+	sess := s.Session()
+
+	err := sess.Save(&Account{Name: "Pressly"})
+	s.Require().NoError(err)
+
+	// Inserts get the values of the scope.
+	upper := ScopedAccount{Name: "Upper"}
+	err = sess.Save(&upper)
+	s.Require().NoError(err)
+	s.True(upper.Disabled)
+
+	count, err := DisabledAccounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(1), count)
+
+	count, err = DisabledAccounts(sess).Find().Unscoped().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(2), count)
+
+	var accounts []ScopedAccount
+	err = DisabledAccounts(sess).Find().All(&accounts)
+	s.Require().NoError(err)
+	s.Require().Len(accounts, 1)
+	s.Equal("Upper", accounts[0].Name)
+
+	// Records outside of the scope can't be fetched through the store.
+	var pressly Account
+	err = Accounts(sess).Find(db.Cond{"name": "Pressly"}).One(&pressly)
+	s.Require().NoError(err)
+
+	var scoped ScopedAccount
+	err = sess.Get(&scoped, pressly.ID)
+	s.ErrorIs(err, db.ErrNoMoreRows)
+
+	err = sess.Get(&scoped, upper.ID)
+	s.Require().NoError(err)
+	s.Equal("Upper", scoped.Name)
+
+	// Updates and deletes are limited to the scope.
+	err = DisabledAccounts(sess).Find().Update(db.Cond{"name": "Renamed"})
+	s.Require().NoError(err)
+
+	err = Accounts(sess).Find(pressly.ID).One(&pressly)
+	s.Require().NoError(err)
+	s.Equal("Pressly", pressly.Name)
+
+	upper.Name = "Upper DB"
+	err = sess.Save(&upper)
+	s.Require().NoError(err)
+
+	err = sess.Delete(&ScopedAccount{ID: pressly.ID})
+	s.Require().NoError(err)
+
+	count, err = Accounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(2), count)
+
+	err = DisabledAccounts(sess).Find().Delete()
+	s.Require().NoError(err)
+
+	count, err = Accounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(1), count)
+}
+
+func (s *RecordTestSuite) TestCodecs() {
+	sess := s.Session()
+
+	db.RegisterCodec("aes", db.AESCodec(db.StaticKey([]byte("0123456789abcdef"))))
+
+	sess.SetRecordTracking(true)
+	defer sess.SetRecordTracking(false)
+
+	account := EncryptedAccount{Name: "Pressly"}
+	err := sess.Save(&account)
+	s.Require().NoError(err)
+	s.Equal("Pressly", account.Name)
+
+	// The value is stored encrypted.
+	var stored Account
+	err = Accounts(sess).Find(account.ID).One(&stored)
+	s.Require().NoError(err)
+	s.NotEmpty(stored.Name)
+	s.NotEqual("Pressly", stored.Name)
+
+	var fetched EncryptedAccount
+	err = sess.Get(&fetched, account.ID)
+	s.Require().NoError(err)
+	s.Equal("Pressly", fetched.Name)
+
+	// Encrypting the same value again is not seen as a change.
+	s.Empty(sess.Changed(&fetched))
+
+	fetched.Name = "Upper"
+	err = sess.Save(&fetched)
+	s.Require().NoError(err)
+
+	err = sess.Get(&fetched, account.ID)
+	s.Require().NoError(err)
+	s.Equal("Upper", fetched.Name)
+}
+
+func (s *RecordTestSuite) TestContextHooks() {
+	sess := s.Session().WithContext(context.WithValue(context.Background(), hookContextKey{}, "req"))
+
+	account := HookedAccount{Name: "Pressly"}
+	err := sess.Save(&account)
+	s.Require().NoError(err)
+	s.Equal([]string{"BeforeSave:req", "BeforeCreate:req", "AfterCreate:req", "AfterSave:req"}, account.Hooks)
+
+	account.Hooks = nil
+	account.Name = "Pressly Inc."
+	err = sess.Save(&account)
+	s.Require().NoError(err)
+	s.Equal([]string{"BeforeSave:req", "BeforeUpdate:req", "AfterUpdate:req", "AfterSave:req"}, account.Hooks)
+
+	var fetched HookedAccount
+	err = sess.Get(&fetched, account.ID)
+	s.Require().NoError(err)
+	s.Equal([]string{"AfterFind:req"}, fetched.Hooks)
+
+	var all []HookedAccount
+	err = Accounts(sess).Find().All(&all)
+	s.Require().NoError(err)
+	s.Require().Len(all, 1)
+	s.Equal([]string{"AfterFind:req"}, all[0].Hooks)
+
+	res := Accounts(sess).Find()
+	defer res.Close()
+
+	var next HookedAccount
+	s.True(res.Next(&next))
+	s.Equal([]string{"AfterFind:req"}, next.Hooks)
+}
+
+func (s *RecordTestSuite) TestDirtyTracking() {
+	sess := s.Session()
+
+	// Records are not tracked by default.
+	account := Account{Name: "Pressly"}
+	err := sess.Save(&account)
+	s.Require().NoError(err)
+	s.Contains(sess.Changed(&account), "name")
+
+	sess.SetRecordTracking(true)
+	defer sess.SetRecordTracking(false)
+
+	// Only records that keep a snapshot are tracked.
+	err = sess.Save(&account)
+	s.Require().NoError(err)
+	s.Contains(sess.Changed(&account), "name")
+
+	tracked := TrackedAccount{Name: "Pressly"}
+	err = sess.Save(&tracked)
+	s.Require().NoError(err)
+	s.Empty(sess.Changed(&tracked))
+
+	// Overwriting the record discards its snapshot.
+	tracked = TrackedAccount{ID: tracked.ID, Name: "Pressly"}
+	s.Contains(sess.Changed(&tracked), "name")
+
+	var fetched TrackedAccount
+	err = sess.Get(&fetched, tracked.ID)
+	s.Require().NoError(err)
+	s.Empty(sess.Changed(&fetched))
+
+	// A concurrent change to a column the record does not modify.
+	err = Accounts(sess).Find(tracked.ID).Update(map[string]interface{}{"disabled": true})
+	s.Require().NoError(err)
+
+	fetched.Name = "Pressly Inc."
+	s.Equal([]string{"name"}, sess.Changed(&fetched))
+
+	err = sess.Save(&fetched)
+	s.Require().NoError(err)
+	s.Empty(sess.Changed(&fetched))
+
+	// The concurrent change was not overwritten.
+	s.True(fetched.Disabled)
+
+	var stored TrackedAccount
+	err = sess.Get(&stored, tracked.ID)
+	s.Require().NoError(err)
+	s.Equal("Pressly Inc.", stored.Name)
+	s.True(stored.Disabled)
+
+	// Saving an unchanged record writes nothing.
+	err = Accounts(sess).Find(tracked.ID).Update(map[string]interface{}{"name": "Upper"})
+	s.Require().NoError(err)
+
+	err = sess.Save(&stored)
+	s.Require().NoError(err)
+
+	err = sess.Get(&stored, tracked.ID)
+	s.Require().NoError(err)
+	s.Equal("Upper", stored.Name)
+
+	// Rolling back restores the snapshot the record had before.
+	err = sess.Tx(func(tx db.Session) error {
+		stored.Name = "Rolled back"
+		if err := tx.Save(&stored); err != nil {
+			return err
+		}
+		s.Empty(tx.Changed(&stored))
+		return errors.New("rollback")
+	})
+	s.Error(err)
+	s.Equal([]string{"name"}, sess.Changed(&stored))
+
+	// Untracked records report every column.
+	s.Contains(sess.Changed(&Account{}), "name")
+}
+
+func (s *RecordTestSuite) TestBulkRecords() {
+	sess := s.Session()
+
+	accounts := []Account{{Name: "Pressly"}, {Name: "Upper"}, {Name: "Golang"}}
+	err := sess.CreateAll(accounts)
+	s.Require().NoError(err)
+
+	for i := range accounts {
+		s.NotZero(accounts[i].ID)
+		s.NotNil(accounts[i].CreatedAt)
+	}
+
+	// AfterCreate hooks ran for every record.
+	count, err := Logs(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(3), count)
+
+	// SaveAll updates existing records and creates new ones.
+	accounts[0].Name = "Pressly Inc."
+	records := []*Account{&accounts[0], {Name: "Gophers"}}
+	err = sess.SaveAll(records)
+	s.Require().NoError(err)
+	s.NotZero(records[1].ID)
+
+	var account Account
+	err = sess.Get(&account, accounts[0].ID)
+	s.Require().NoError(err)
+	s.Equal("Pressly Inc.", account.Name)
+
+	count, err = Accounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(4), count)
+
+	// Existing records are updated in batches, each one with its own values.
+	accounts[1].Name = "Upper Inc."
+	accounts[2].Disabled = true
+	err = sess.SaveAll(accounts)
+	s.Require().NoError(err)
+
+	var stored []Account
+	err = Accounts(sess).Find(db.Cond{"id": db.In(accounts[0].ID, accounts[1].ID, accounts[2].ID)}).OrderBy("id").All(&stored)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 3)
+	s.Equal("Pressly Inc.", stored[0].Name)
+	s.False(stored[0].Disabled)
+	s.Equal("Upper Inc.", stored[1].Name)
+	s.False(stored[1].Disabled)
+	s.Equal("Golang", stored[2].Name)
+	s.True(stored[2].Disabled)
+
+	// DeleteAll deletes every record.
+	err = sess.DeleteAll(&accounts)
+	s.Require().NoError(err)
+
+	count, err = Accounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Equal(uint64(1), count)
+}
+
+func (s *RecordTestSuite) TestBulkRecordErrors() {
+	sess := s.Session()
+
+	accounts := []*ValidatedAccount{{Name: "Pressly"}, {}, {Name: "Upper"}, {}}
+	err := sess.CreateAll(accounts)
+	s.Require().Error(err)
+
+	var errs db.RecordErrors
+	s.Require().True(errors.As(err, &errs))
+	s.Require().Len(errs, 2)
+	s.Equal(1, errs[0].Index)
+	s.Equal(accounts[1], errs[0].Record)
+	s.Equal(3, errs[1].Index)
+
+	// Nothing was persisted.
+	count, err := Accounts(sess).Find().Count()
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	err = sess.CreateAll([]*ValidatedAccount{{Name: "Pressly"}, nil})
+	s.ErrorIs(err, db.ErrNilRecord)
+
+	err = sess.CreateAll(ValidatedAccount{Name: "Pressly"})
+	s.Error(err)
+
+	err = sess.CreateAll([]db.Record{&Account{Name: "Pressly"}, &ValidatedAccount{Name: "Upper"}})
+	s.ErrorIs(err, db.ErrMixedRecordTypes)
+}
+
 func (s *RecordTestSuite) TestSlices() {
 	sess := s.Session()
 
@@ -161,6 +161,47 @@ func (s *SQLTestSuite) TestPreparedStatementsCache() {
 	sess.SetMaxOpenConns(0)
 }
 
+func (s *SQLTestSuite) TestPreparedStatementsCacheWithinTransaction() {
+	sess := s.Session()
+
+	sess.SetPreparedStatementCache(true)
+	defer sess.SetPreparedStatementCache(false)
+
+	sess.SetPreparedStatementCacheCapacity(2)
+	defer sess.SetPreparedStatementCacheCapacity(db.DefaultSettings.PreparedStatementCacheCapacity())
+
+	reporter := sess.(db.StatsReporter)
+	before := reporter.Stats()
+
+	for i := 0; i < 2; i++ {
+		err := sess.Tx(func(tx db.Session) error {
+			for j := 0; j < 3; j++ {
+				if _, err := tx.Collection("artist").Find().Count(); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		s.Require().NoError(err)
+	}
+
+	after := reporter.Stats()
+
+	// The first lookup prepares the statement on the database, the following
+	// ones reuse it within each transaction.
+	s.True(after.PreparedStatementCacheMisses > before.PreparedStatementCacheMisses)
+	s.True(after.PreparedStatementCacheHits-before.PreparedStatementCacheHits >= 4)
+
+	for i := 0; i < 5; i++ {
+		res := sess.Collection("artist").Find().Select(db.Raw(fmt.Sprintf("count(%d) AS c", i)))
+
+		var count map[string]uint64
+		s.Require().NoError(res.One(&count))
+	}
+
+	s.True(reporter.Stats().PreparedStatementCacheEvictions >= 3)
+}
+
 func (s *SQLTestSuite) TestTruncateAllCollections() {
 	sess := s.Session()
 
@@ -196,6 +237,98 @@ func (s *SQLTestSuite) TestQueryLogger() {
 	s.Error(err)
 }
 
+func (s *SQLTestSuite) TestStats() {
+	sess := s.Session()
+
+	reporter, ok := sess.(db.StatsReporter)
+	s.Require().True(ok)
+
+	_, err := sess.Collection("artist").Insert(artistType{Name: "Ozzie"})
+	s.Require().NoError(err)
+
+	_, err = sess.Collection("artist").Find().Count()
+	s.Require().NoError(err)
+
+	_, err = sess.Collection("artist_x").Find().Count()
+	s.Error(err)
+
+	stats := reporter.Stats()
+
+	var selects, inserts, failed uint64
+	for _, qs := range stats.Queries {
+		switch {
+		case qs.Type == "SELECT" && qs.Table == "artist":
+			selects += qs.Count
+		case qs.Type == "INSERT" && qs.Table == "artist":
+			inserts += qs.Count
+		}
+		failed += qs.Errors
+	}
+
+	s.NotZero(selects)
+	s.NotZero(inserts)
+	s.NotZero(failed)
+	s.NotZero(stats.DB.OpenConnections)
+}
+
+func (s *SQLTestSuite) TestExplain() {
+	sess := s.Session()
+
+	_, err := sess.Collection("artist").Insert(artistType{Name: "Ozzie"})
+	s.Require().NoError(err)
+
+	q := sess.SQL().SelectFrom("artist").Where("name = ?", "Ozzie")
+
+	if s.Adapter() == "ql" {
+		_, err := q.Explain(nil, nil)
+		s.ErrorIs(err, db.ErrNotSupportedByAdapter)
+		return
+	}
+
+	plan, err := q.Explain(nil, nil)
+	s.Require().NoError(err)
+	s.NotEmpty(plan.Raw)
+	s.NotEmpty(plan.Nodes)
+
+	plan, err = sess.Collection("artist").Find(db.Cond{"name": "Ozzie"}).Explain(context.Background(), nil)
+	s.Require().NoError(err)
+	s.NotEmpty(plan.Raw)
+	s.NotEmpty(plan.Nodes)
+
+	if s.Adapter() != "sqlite" {
+		plan, err = q.Explain(context.Background(), &db.ExplainOptions{Analyze: true})
+		s.Require().NoError(err)
+		s.NotEmpty(plan.Nodes)
+	}
+}
+
+func (s *SQLTestSuite) TestUniqueViolationError() {
+	if s.Adapter() == "ql" {
+		s.T().Skip("ql does not report constraint violations")
+	}
+
+	sess := s.Session()
+
+	users := sess.Collection("users")
+	s.Require().NoError(users.Truncate())
+
+	_, err := users.Insert(map[string]interface{}{"username": "joe"})
+	s.Require().NoError(err)
+
+	_, err = users.Insert(map[string]interface{}{"username": "joe"})
+	s.Require().Error(err)
+	s.True(errors.Is(err, db.ErrUniqueViolation), "%v", err)
+
+	var dbErr *db.DatabaseError
+	s.True(errors.As(err, &dbErr))
+
+	err = sess.Tx(func(tx db.Session) error {
+		_, err := tx.Collection("users").Insert(map[string]interface{}{"username": "joe"})
+		return err
+	})
+	s.True(errors.Is(err, db.ErrUniqueViolation), "%v", err)
+}
+
 func (s *SQLTestSuite) TestExpectCursorError() {
 	sess := s.Session()
 
@@ -1963,6 +2096,172 @@ func (s *SQLTestSuite) TestSelectFromSubquery() {
 	}
 }
 
+func (s *SQLTestSuite) TestSelectNestedStructs() {
+	sess := s.Session()
+
+	var artist artistType
+	err := sess.Collection("artist").Find(db.Cond{"name": "Ozzie"}).One(&artist)
+	s.Require().NoError(err)
+
+	_, err = sess.Collection("publication").Insert(map[string]interface{}{"title": "Blizzard of Ozz", "author_id": artist.ID})
+	s.Require().NoError(err)
+	_, err = sess.Collection("publication").Insert(map[string]interface{}{"title": "Anonymous", "author_id": 9999})
+	s.Require().NoError(err)
+
+	type publicationWithAuthor struct {
+		ID     int64       `db:"id"`
+		Title  string      `db:"title"`
+		Author *artistType `db:"author,prefix=author."`
+	}
+
+	var publications []publicationWithAuthor
+	err = sess.SQL().
+		Select(publicationWithAuthor{}).
+		From("publication").
+		LeftJoin("artist AS author").On("author.id = publication.author_id").
+		OrderBy("publication.title").
+		All(&publications)
+	s.Require().NoError(err)
+	s.Require().Len(publications, 2)
+
+	// The author of the first publication does not exist.
+	s.Equal("Anonymous", publications[0].Title)
+	s.Nil(publications[0].Author)
+
+	s.Equal("Blizzard of Ozz", publications[1].Title)
+	s.NotZero(publications[1].ID)
+	s.Require().NotNil(publications[1].Author)
+	s.Equal(artist.ID, publications[1].Author.ID)
+	s.Equal("Ozzie", publications[1].Author.Name)
+}
+
+func (s *SQLTestSuite) TestFieldMapping() {
+	sess := s.Session()
+
+	sess.SetFieldMapping(db.FieldMapping{NameFunc: db.SnakeCase})
+	defer sess.SetFieldMapping(db.FieldMapping{})
+
+	type untaggedArtist struct {
+		ID   int64 `db:"id,omitempty"`
+		Name string
+	}
+
+	artist := untaggedArtist{Name: "Untagged"}
+	err := sess.Collection("artist").InsertReturning(&artist)
+	s.Require().NoError(err)
+	s.NotZero(artist.ID)
+
+	var found untaggedArtist
+	err = sess.Collection("artist").Find(db.Cond{"id": artist.ID}).One(&found)
+	s.Require().NoError(err)
+	s.Equal(artist, found)
+
+	// Transactions inherit the field mapping of the session.
+	err = sess.Tx(func(tx db.Session) error {
+		var found untaggedArtist
+		if err := tx.SQL().SelectFrom("artist").Where("id = ?", artist.ID).One(&found); err != nil {
+			return err
+		}
+		s.Equal("Untagged", found.Name)
+		return nil
+	})
+	s.Require().NoError(err)
+}
+
+func (s *SQLTestSuite) TestSelectorChunk() {
+	sess := s.Session()
+
+	artist := sess.Collection("artist")
+	err := artist.Truncate()
+	s.Require().NoError(err)
+
+	for _, name := range []string{"Ozzie", "Flea", "Slash", "Chrono", "Rin"} {
+		_, err := artist.Insert(map[string]string{"name": name})
+		s.Require().NoError(err)
+	}
+
+	type artistType struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	sel := sess.SQL().SelectFrom("artist")
+
+	{
+		var batch []artistType
+		var names []string
+		err := sel.Chunk(nil, 2, func(sess db.Session, _ interface{}) error {
+			for _, item := range batch {
+				names = append(names, item.Name)
+			}
+			return nil
+		}, &db.ChunkOptions{Into: &batch, Key: "id"})
+		s.Require().NoError(err)
+		s.Equal([]string{"Ozzie", "Flea", "Slash", "Chrono", "Rin"}, names)
+	}
+
+	{
+		var batch []artistType
+		var names []string
+		err := sel.OrderBy("name").Chunk(nil, 2, func(sess db.Session, _ interface{}) error {
+			for _, item := range batch {
+				names = append(names, item.Name)
+			}
+			return nil
+		}, &db.ChunkOptions{Into: &batch})
+		s.Require().NoError(err)
+		s.Equal([]string{"Chrono", "Flea", "Ozzie", "Rin", "Slash"}, names)
+	}
+
+	{
+		// Each chunk is processed within its own transaction, the transaction
+		// of a failing chunk is rolled back.
+		var batch []artistType
+		errFailed := errors.New("failed")
+		err := artist.Find().Chunk(nil, 2, func(tx db.Session, _ interface{}) error {
+			for _, item := range batch {
+				if err := tx.Collection("artist").Find(item.ID).Update(map[string]string{"name": item.Name + "!"}); err != nil {
+					return err
+				}
+			}
+			if batch[0].Name == "Slash" {
+				return errFailed
+			}
+			return nil
+		}, &db.ChunkOptions{Into: &batch, Transaction: true})
+		s.ErrorIs(err, errFailed)
+
+		var names []string
+		err = artist.Find().OrderBy("id").Pluck("name", &names)
+		s.Require().NoError(err)
+		s.Equal([]string{"Ozzie!", "Flea!", "Slash", "Chrono", "Rin"}, names)
+	}
+
+	{
+		// A chunk whose transaction is retried is processed again.
+		retries := sess.MaxTransactionRetries()
+		sess.SetMaxTransactionRetries(2)
+		defer sess.SetMaxTransactionRetries(retries)
+
+		var batch []artistType
+		var ids []int64
+		aborted := false
+		err := artist.Find().Chunk(nil, 2, func(tx db.Session, _ interface{}) error {
+			for _, item := range batch {
+				ids = append(ids, item.ID)
+			}
+			if len(ids) == 4 && !aborted {
+				aborted = true
+				return db.ErrTransactionAborted
+			}
+			return nil
+		}, &db.ChunkOptions{Into: &batch, Transaction: true})
+		s.Require().NoError(err)
+		s.Require().Len(ids, 7)
+		s.Equal(ids[2:4], ids[4:6])
+	}
+}
+
 var (
 	_ = db.Marshaler(&customType{})
 	_ = db.Unmarshaler(&customType{})
@@ -0,0 +1,290 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+// validationRules are the tag options that declare validation rules, in the
+// order they're checked.
+var validationRules = []string{"required", "minlen", "maxlen", "min", "max", "match", "oneof"}
+
+var validationMapper = reflectx.NewMapper("db")
+
+// fieldMapperSession is satisfied by the sessions of SQL adapters, which map
+// the fields of structs as set by Settings.SetFieldMapping.
+type fieldMapperSession interface {
+	FieldMapper() *reflectx.Mapper
+}
+
+var validationRegexps sync.Map
+
+// FieldError describes a field that did not pass one of its validation rules.
+type FieldError struct {
+	// Field is the name of the struct field.
+	Field string
+
+	// Column is the name of the column the field is mapped to.
+	Column string
+
+	// Rule is the name of the rule that failed, like "required" or "maxlen".
+	Rule string
+
+	// Message describes the error.
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Column, e.Message)
+}
+
+// ValidationError is returned when a record does not pass the validation rules
+// declared by its struct tags, it holds an error for every field that failed.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i := range e.Fields {
+		messages[i] = e.Fields[i].Error()
+	}
+	return "upper: validation failed: " + strings.Join(messages, "; ")
+}
+
+// Field returns the errors of the field mapped to the given column.
+func (e *ValidationError) Field(column string) []*FieldError {
+	var errs []*FieldError
+	for i := range e.Fields {
+		if e.Fields[i].Column == column {
+			errs = append(errs, e.Fields[i])
+		}
+	}
+	return errs
+}
+
+// Validate checks the validation rules declared by the db struct tags of item,
+// which must be a struct or a pointer to a struct. A *ValidationError is
+// returned if any field fails its rules. Session.Save validates records
+// automatically before creating or updating them.
+//
+// Rules are declared as tag options:
+//
+//	type Account struct {
+//		Name  string `db:"name,required,minlen=2,maxlen=64"`
+//		Age   int    `db:"age,min=18,max=130"`
+//		Email string `db:"email,match=^[^@]+@[^@]+$"`
+//		Plan  string `db:"plan,oneof=free|pro|enterprise"`
+//	}
+//
+// The required rule rejects zero values, nil pointers and empty strings,
+// slices and maps. The minlen and maxlen rules limit the length of strings
+// (in characters), slices and maps, while min and max limit the value of
+// numbers. The match rule expects strings to match a regular expression,
+// which can't contain commas, and oneof expects values to be one of the
+// values separated by "|". Rules other than required are not checked on nil
+// pointers.
+//
+// Validate maps fields to columns using the default field mapping, use
+// ValidateSession to report errors under the columns a session maps fields
+// to.
+func Validate(item interface{}) error {
+	return validate(validationMapper, item)
+}
+
+// ValidateSession is like Validate but it maps the fields of item to columns
+// as the given session does, see Settings.SetFieldMapping.
+func ValidateSession(sess Session, item interface{}) error {
+	if m, ok := sess.(fieldMapperSession); ok {
+		if mapper := m.FieldMapper(); mapper != nil {
+			return validate(mapper, item)
+		}
+	}
+	return validate(validationMapper, item)
+}
+
+func validate(mapper *reflectx.Mapper, item interface{}) error {
+	itemV := reflect.Indirect(reflect.ValueOf(item))
+	if itemV.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fieldErrs []*FieldError
+	for _, fi := range mapper.TypeMap(itemV.Type()).Index {
+		if fi.Embedded || !hasValidationRules(fi) {
+			continue
+		}
+
+		fieldV := reflectx.ValidFieldByIndexes(itemV, fi.Index)
+		for fieldV.Kind() == reflect.Ptr && !fieldV.IsNil() {
+			fieldV = fieldV.Elem()
+		}
+		if fieldV.Kind() == reflect.Ptr {
+			fieldV = reflect.Value{}
+		}
+
+		for _, rule := range validationRules {
+			arg, ok := fi.Options[rule]
+			if !ok {
+				continue
+			}
+			message, err := checkRule(rule, arg, fieldV)
+			if err != nil {
+				return fmt.Errorf("upper: invalid %q rule on field %q: %w", rule, fi.Field.Name, err)
+			}
+			if message != "" {
+				fieldErrs = append(fieldErrs, &FieldError{
+					Field:   fi.Field.Name,
+					Column:  fi.Name,
+					Rule:    rule,
+					Message: message,
+				})
+				if rule == "required" {
+					// Other rules would fail as well.
+					break
+				}
+			}
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return &ValidationError{Fields: fieldErrs}
+	}
+	return nil
+}
+
+func hasValidationRules(fi *reflectx.FieldInfo) bool {
+	for _, rule := range validationRules {
+		if _, ok := fi.Options[rule]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRule returns a message if value does not pass the rule, an invalid
+// value means the field is nil.
+func checkRule(rule string, arg string, value reflect.Value) (string, error) {
+	if rule == "required" {
+		if !value.IsValid() || value.IsZero() || (hasLen(value) && value.Len() == 0) {
+			return "is required", nil
+		}
+		return "", nil
+	}
+	if !value.IsValid() {
+		return "", nil
+	}
+
+	switch rule {
+	case "minlen", "maxlen":
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return "", err
+		}
+		if !hasLen(value) {
+			return "", fmt.Errorf("expecting a string, slice or map, got %v", value.Type())
+		}
+		length, unit := value.Len(), "items"
+		if value.Kind() == reflect.String {
+			length, unit = utf8.RuneCountInString(value.String()), "characters"
+		}
+		if rule == "minlen" && length < n {
+			return fmt.Sprintf("must have at least %d %s", n, unit), nil
+		}
+		if rule == "maxlen" && length > n {
+			return fmt.Sprintf("must have at most %d %s", n, unit), nil
+		}
+	case "min", "max":
+		n, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return "", err
+		}
+		var number float64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number = float64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number = float64(value.Uint())
+		case reflect.Float32, reflect.Float64:
+			number = value.Float()
+		default:
+			return "", fmt.Errorf("expecting a number, got %v", value.Type())
+		}
+		if rule == "min" && number < n {
+			return fmt.Sprintf("must be at least %s", arg), nil
+		}
+		if rule == "max" && number > n {
+			return fmt.Sprintf("must be at most %s", arg), nil
+		}
+	case "match":
+		if value.Kind() != reflect.String {
+			return "", fmt.Errorf("expecting a string, got %v", value.Type())
+		}
+		re, err := validationRegexp(arg)
+		if err != nil {
+			return "", err
+		}
+		if !re.MatchString(value.String()) {
+			return fmt.Sprintf("must match %s", arg), nil
+		}
+	case "oneof":
+		allowed := strings.Split(arg, "|")
+		current := fmt.Sprintf("%v", value.Interface())
+		for i := range allowed {
+			if current == allowed[i] {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("must be one of %s", strings.Join(allowed, ", ")), nil
+	}
+
+	return "", nil
+}
+
+func hasLen(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+	return false
+}
+
+func validationRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := validationRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	validationRegexps.Store(expr, re)
+	return re, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2012-present The upper.io/db authors. All rights reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4/internal/reflectx"
+)
+
+type validatedItem struct {
+	ID    uint64  `db:"id,omitempty"`
+	Name  string  `db:"name,required,minlen=2,maxlen=5"`
+	Age   int     `db:"age,min=18,max=130"`
+	Email *string `db:"email,match=^[^@]+@[^@]+$"`
+	Plan  string  `db:"plan,oneof=free|pro"`
+	Tags  []int   `db:"tags,maxlen=2"`
+}
+
+func TestValidate(t *testing.T) {
+	email := "user@example.com"
+
+	valid := validatedItem{Name: "Joe", Age: 30, Email: &email, Plan: "pro"}
+	assert.NoError(t, Validate(&valid))
+	assert.NoError(t, Validate(valid))
+
+	invalidEmail := "nope"
+	invalid := validatedItem{Name: "Ñandú Jr.", Age: 12, Email: &invalidEmail, Plan: "gold", Tags: []int{1, 2, 3}}
+
+	err := Validate(&invalid)
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Len(t, validationErr.Fields, 5)
+
+		if assert.Len(t, validationErr.Field("name"), 1) {
+			assert.Equal(t, "maxlen", validationErr.Field("name")[0].Rule)
+			assert.Equal(t, "Name", validationErr.Field("name")[0].Field)
+		}
+		assert.Equal(t, "min", validationErr.Field("age")[0].Rule)
+		assert.Equal(t, "match", validationErr.Field("email")[0].Rule)
+		assert.Equal(t, "oneof", validationErr.Field("plan")[0].Rule)
+		assert.Equal(t, "maxlen", validationErr.Field("tags")[0].Rule)
+	}
+
+	err = Validate(&validatedItem{Name: "J", Age: 18, Plan: "free"})
+	assert.EqualError(t, err, "upper: validation failed: name must have at least 2 characters")
+
+	err = Validate(&validatedItem{Age: 18, Plan: "free", Tags: []int{1, 2, 3}})
+	assert.EqualError(t, err, "upper: validation failed: name is required; tags must have at most 2 items")
+
+	err = Validate(&struct {
+		Name string `db:"name,maxlen=five"`
+	}{})
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &validationErr))
+
+	assert.NoError(t, Validate(nil))
+}
+
+type mappedSession struct {
+	Session
+	mapper *reflectx.Mapper
+}
+
+func (s *mappedSession) FieldMapper() *reflectx.Mapper {
+	return s.mapper
+}
+
+func TestValidateSession(t *testing.T) {
+	item := &struct {
+		FirstName string `db:",required"`
+	}{}
+
+	var verr *ValidationError
+
+	err := ValidateSession(&mappedSession{mapper: reflectx.NewMapperTagFunc("db", SnakeCase, nil)}, item)
+	assert.True(t, errors.As(err, &verr))
+	assert.Len(t, verr.Field("first_name"), 1)
+
+	// Without a session the default field mapping is used.
+	err = ValidateSession(nil, item)
+	assert.True(t, errors.As(err, &verr))
+	assert.Empty(t, verr.Field("first_name"))
+}